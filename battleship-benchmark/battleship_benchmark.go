@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	_ "github.com/mtratsiuk/battleship/battleship-go-strategy"
+)
+
+// ConfigEnv names the environment variable pointing to the benchmark config file.
+const ConfigEnv = "BATTLESHIP_BENCHMARK_CONFIG"
+
+type Config struct {
+	Targeting string   `yaml:"targeting" toml:"targeting" env:"BATTLESHIP_BENCHMARK_TARGETING" flag:"targeting" usage:"targeting strategy name to benchmark"`
+	Placement string   `yaml:"placement" toml:"placement" env:"BATTLESHIP_BENCHMARK_PLACEMENT" flag:"placement" usage:"placement strategy name placing the fleets to sink"`
+	Fleets    int      `yaml:"fleets" toml:"fleets" env:"BATTLESHIP_BENCHMARK_FLEETS" flag:"fleets" usage:"number of fleets to sink"`
+	Seed      int      `yaml:"seed" toml:"seed" env:"BATTLESHIP_BENCHMARK_SEED" flag:"seed" usage:"seed of the first fleet, fleet i is seeded with seed+i"`
+	Workers   int      `yaml:"workers" toml:"workers" env:"BATTLESHIP_BENCHMARK_WORKERS" flag:"workers" usage:"maximum number of fleets played at once"`
+	BinWidth  int      `yaml:"bin_width" toml:"bin_width" env:"BATTLESHIP_BENCHMARK_BIN_WIDTH" flag:"bin-width" usage:"number of shots of a histogram bin"`
+	Output    string   `yaml:"output" toml:"output" env:"BATTLESHIP_BENCHMARK_OUTPUT" flag:"output" usage:"file to write the JSON result to"`
+	Compare   []string `yaml:"compare" toml:"compare" env:"BATTLESHIP_BENCHMARK_COMPARE" flag:"compare" usage:"baseline and candidate result files to compare instead of running a benchmark"`
+	Alpha     float64  `yaml:"alpha" toml:"alpha" env:"BATTLESHIP_BENCHMARK_ALPHA" flag:"alpha" usage:"significance level of the comparison"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.Targeting = "random"
+	c.Placement = "random"
+	c.Fleets = 2000
+	c.Seed = 1
+	c.Workers = runtime.NumCPU()
+	c.BinWidth = 5
+	c.Compare = make([]string, 0)
+	c.Alpha = 0.05
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if len(c.Compare) != 0 {
+		if len(c.Compare) != 2 {
+			errs = append(errs, errors.New("compare expects two result files: baseline and candidate"))
+		}
+
+		if c.Alpha <= 0 || c.Alpha >= 1 {
+			errs = append(errs, errors.New("alpha must be between 0 and 1"))
+		}
+
+		return errors.Join(errs...)
+	}
+
+	if _, err := core.NewTargetingStrategy(c.Targeting); err != nil {
+		errs = append(errs, fmt.Errorf("targeting: %w", err))
+	}
+
+	if _, err := core.NewPlacementStrategy(c.Placement); err != nil {
+		errs = append(errs, fmt.Errorf("placement: %w", err))
+	}
+
+	if c.Fleets < 1 {
+		errs = append(errs, errors.New("fleets must be positive"))
+	}
+
+	if c.Workers < 1 {
+		errs = append(errs, errors.New("workers must be positive"))
+	}
+
+	if c.BinWidth < 1 {
+		errs = append(errs, errors.New("bin_width must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	if len(config.Compare) != 0 {
+		compare(config)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := RunBenchmark(ctx, config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchmark failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if config.Output != "" {
+		if err := WriteResultFile(config.Output, result); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write result: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if err := WriteTextResult(os.Stdout, result, config.BinWidth); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write result: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func compare(config Config) {
+	baseline, err := ReadResultFile(config.Compare[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read baseline: %v\n", err)
+		os.Exit(2)
+	}
+
+	candidate, err := ReadResultFile(config.Compare[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read candidate: %v\n", err)
+		os.Exit(2)
+	}
+
+	c := NewComparison(baseline, candidate, config.Alpha)
+
+	if err := WriteTextComparison(os.Stdout, c); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write comparison: %v\n", err)
+		os.Exit(2)
+	}
+}
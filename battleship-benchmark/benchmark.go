@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// Result is a benchmark of a targeting strategy: the number of shots it took
+// to sink every fleet. Fleets only depend on the placement strategy and seed,
+// so results sharing them were played against the same fleets.
+type Result struct {
+	Targeting string `json:"targeting"`
+	Placement string `json:"placement"`
+	Seed      int    `json:"seed"`
+	// Shots is the number of shots to sink fleet i.
+	Shots   []int   `json:"shots"`
+	Summary Summary `json:"summary"`
+}
+
+// SameFleets reports whether both results were played against the same fleets.
+func (r Result) SameFleets(other Result) bool {
+	return r.Placement == other.Placement && r.Seed == other.Seed && len(r.Shots) == len(other.Shots)
+}
+
+// RunBenchmark sinks config.Fleets fleets with the targeting strategy, fleet i
+// is placed and struck with an rng seeded with config.Seed+i.
+func RunBenchmark(ctx context.Context, config Config) (Result, error) {
+	r := Result{}
+	r.Targeting = config.Targeting
+	r.Placement = config.Placement
+	r.Seed = config.Seed
+	r.Shots = make([]int, config.Fleets)
+
+	errs := make([]error, config.Fleets)
+	sem := make(chan struct{}, config.Workers)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < config.Fleets && ctx.Err() == nil; i += 1 {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			r.Shots[i], errs[i] = SinkFleet(ctx, config.Placement, config.Targeting, rand.New(rand.NewSource(int64(config.Seed+i))))
+		}(i)
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return r, err
+	}
+
+	for i, err := range errs {
+		if err != nil {
+			return r, fmt.Errorf("fleet %v: %w", i, err)
+		}
+	}
+
+	r.Summary = NewSummary(r.Shots)
+
+	return r, nil
+}
+
+// SinkFleet places a fleet and strikes it until it's sunk, returning the
+// number of shots. Repeated shots count, and a strategy that didn't sink the
+// fleet within core.BattleshipGameTurnsLimit shots fails.
+func SinkFleet(ctx context.Context, placementName, targetingName string, rng *rand.Rand) (int, error) {
+	placement, err := core.NewPlacementStrategy(placementName)
+	if err != nil {
+		return 0, err
+	}
+
+	targeting, err := core.NewTargetingStrategy(targetingName)
+	if err != nil {
+		return 0, err
+	}
+
+	field, err := placement.Place(ctx, rng)
+	if err != nil {
+		return 0, fmt.Errorf("failed to place fleet: %w", err)
+	}
+
+	if err := field.Validate(); err != nil {
+		return 0, fmt.Errorf("placed invalid fleet: %w", err)
+	}
+
+	view := core.NewBattleshipOpponentView()
+
+	for shots := 1; shots <= core.BattleshipGameTurnsLimit; shots += 1 {
+		pos, err := targeting.Target(ctx, &view, rng)
+		if err != nil {
+			return 0, fmt.Errorf("failed to target after %v shots: %w", shots-1, err)
+		}
+
+		if !pos.IsInBounds() {
+			return 0, fmt.Errorf("strike position is out of bounds: %v", pos)
+		}
+
+		field.Strike(pos)
+		view.Mark(pos, !field.Field[pos.Y][pos.X].IsEmpty())
+
+		if !field.HasAliveShips() {
+			return shots, nil
+		}
+	}
+
+	return 0, core.ErrGameTooLong
+}
+
+func WriteResultFile(path string, r Result) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+func ReadResultFile(path string) (Result, error) {
+	r := Result{}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return r, err
+	}
+
+	if err := json.Unmarshal(b, &r); err != nil {
+		return r, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+
+	if len(r.Shots) == 0 {
+		return r, fmt.Errorf("%v has no shots", path)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Summary describes the distribution of shots to sink a fleet.
+type Summary struct {
+	Fleets int     `json:"fleets"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"std_dev"`
+	Min    int     `json:"min"`
+	Median float64 `json:"median"`
+	P90    int     `json:"p90"`
+	Max    int     `json:"max"`
+}
+
+func NewSummary(shots []int) Summary {
+	s := Summary{}
+	s.Fleets = len(shots)
+
+	if len(shots) == 0 {
+		return s
+	}
+
+	sorted := slices.Clone(shots)
+	slices.Sort(sorted)
+
+	sum := 0
+	for _, n := range sorted {
+		sum += n
+	}
+
+	s.Mean = float64(sum) / float64(len(sorted))
+
+	for _, n := range sorted {
+		s.StdDev += (float64(n) - s.Mean) * (float64(n) - s.Mean)
+	}
+
+	if len(sorted) > 1 {
+		s.StdDev = math.Sqrt(s.StdDev / float64(len(sorted)-1))
+	}
+
+	s.Min = sorted[0]
+	s.Max = sorted[len(sorted)-1]
+
+	if mid := len(sorted) / 2; len(sorted)%2 == 1 {
+		s.Median = float64(sorted[mid])
+	} else {
+		s.Median = float64(sorted[mid-1]+sorted[mid]) / 2
+	}
+
+	// nearest rank percentile
+	s.P90 = sorted[int(math.Ceil(0.9*float64(len(sorted))))-1]
+
+	return s
+}
+
+// Bin is a histogram bin counting fleets sunk with From to To shots, inclusive.
+type Bin struct {
+	From  int
+	To    int
+	Count int
+}
+
+func Histogram(shots []int, width int) []Bin {
+	bins := make([]Bin, 0)
+
+	if len(shots) == 0 {
+		return bins
+	}
+
+	first := slices.Min(shots) / width * width
+	last := slices.Max(shots)
+
+	for from := first; from <= last; from += width {
+		bins = append(bins, Bin{From: from, To: from + width - 1})
+	}
+
+	for _, n := range shots {
+		bins[(n-first)/width].Count += 1
+	}
+
+	return bins
+}
+
+// MannWhitneyU tests whether values of a tend to be greater or less than
+// values of b, without assuming their distribution. It returns the U
+// statistic of a, its z score using the normal approximation corrected for
+// ties, and the two-sided p-value.
+func MannWhitneyU(a, b []int) (u, z, p float64) {
+	type value struct {
+		n     int
+		fromA bool
+	}
+
+	values := make([]value, 0, len(a)+len(b))
+	for _, n := range a {
+		values = append(values, value{n, true})
+	}
+	for _, n := range b {
+		values = append(values, value{n, false})
+	}
+
+	slices.SortFunc(values, func(x, y value) int { return x.n - y.n })
+
+	ranksA := 0.0
+	ties := 0.0
+
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].n == values[i].n {
+			j += 1
+		}
+
+		// tied values share the average of their ranks, 1-based
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k += 1 {
+			if values[k].fromA {
+				ranksA += rank
+			}
+		}
+
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n1, n2 := float64(len(a)), float64(len(b))
+	n := n1 + n2
+
+	u = ranksA - n1*(n1+1)/2
+
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 || math.IsNaN(sigma) {
+		return u, 0, 1
+	}
+
+	z = (u - n1*n2/2) / sigma
+	p = math.Erfc(math.Abs(z) / math.Sqrt2)
+
+	return u, z, p
+}
+
+// WilcoxonSignedRank tests whether values of a tend to be greater or less
+// than paired values of b, a[i] being paired with b[i], without assuming
+// their distribution. Pairs with equal values are dropped. It returns the W
+// statistic, the sum of ranks of positive differences a[i]-b[i], its z score
+// using the normal approximation corrected for ties, and the two-sided
+// p-value.
+func WilcoxonSignedRank(a, b []int) (w, z, p float64) {
+	diffs := make([]int, 0, len(a))
+	for i := range a {
+		if d := a[i] - b[i]; d != 0 {
+			diffs = append(diffs, d)
+		}
+	}
+
+	abs := func(n int) int { return max(n, -n) }
+
+	slices.SortFunc(diffs, func(x, y int) int { return abs(x) - abs(y) })
+
+	ties := 0.0
+
+	for i := 0; i < len(diffs); {
+		j := i
+		for j < len(diffs) && abs(diffs[j]) == abs(diffs[i]) {
+			j += 1
+		}
+
+		// tied differences share the average of their ranks, 1-based
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k += 1 {
+			if diffs[k] > 0 {
+				w += rank
+			}
+		}
+
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n := float64(len(diffs))
+
+	sigma := math.Sqrt(n*(n+1)*(2*n+1)/24 - ties/48)
+	if sigma == 0 || math.IsNaN(sigma) {
+		return w, 0, 1
+	}
+
+	z = (w - n*(n+1)/4) / sigma
+	p = math.Erfc(math.Abs(z) / math.Sqrt2)
+
+	return w, z, p
+}
+
+// Comparison compares a candidate result against a baseline, fewer shots
+// being better. Results played against the same fleets are paired by fleet
+// and compared with the Wilcoxon signed-rank test, others with the
+// Mann-Whitney U test.
+type Comparison struct {
+	Baseline  Result
+	Candidate Result
+	// MeanDiff is the candidate mean minus the baseline mean, negative if
+	// the candidate sinks fleets faster.
+	MeanDiff float64
+	// Test is the name of the test used, Statistic is its statistic of the
+	// candidate and Z is negative if the candidate needs fewer shots.
+	Test          string
+	StatisticName string
+	Statistic     float64
+	Z             float64
+	P             float64
+	Alpha         float64
+	Significant   bool
+}
+
+func NewComparison(baseline, candidate Result, alpha float64) Comparison {
+	c := Comparison{}
+	c.Baseline = baseline
+	c.Candidate = candidate
+	c.Alpha = alpha
+
+	c.MeanDiff = NewSummary(candidate.Shots).Mean - NewSummary(baseline.Shots).Mean
+
+	if baseline.SameFleets(candidate) {
+		c.Test, c.StatisticName = "Wilcoxon signed-rank", "W"
+		c.Statistic, c.Z, c.P = WilcoxonSignedRank(candidate.Shots, baseline.Shots)
+	} else {
+		c.Test, c.StatisticName = "Mann-Whitney U", "U"
+		c.Statistic, c.Z, c.P = MannWhitneyU(candidate.Shots, baseline.Shots)
+	}
+	c.Significant = c.P < alpha
+
+	return c
+}
+
+func (c Comparison) Verdict() string {
+	switch {
+	case !c.Significant:
+		return fmt.Sprintf("no significant difference (p >= %v)", c.Alpha)
+	case c.Z < 0:
+		return fmt.Sprintf("candidate is better: it needs fewer shots (p < %v)", c.Alpha)
+	default:
+		return fmt.Sprintf("candidate is worse: it needs more shots (p < %v)", c.Alpha)
+	}
+}
+
+func summaryLine(name string, s Summary) string {
+	return fmt.Sprintf("%-10v %7v %8.2f %8.2f %5v %7.1f %5v %5v", name, s.Fleets, s.Mean, s.StdDev, s.Min, s.Median, s.P90, s.Max)
+}
+
+func summaryHeader() string {
+	return fmt.Sprintf("%-10v %7v %8v %8v %5v %7v %5v %5v", "", "Fleets", "Mean", "StdDev", "Min", "Median", "P90", "Max")
+}
+
+func WriteTextResult(w io.Writer, r Result, binWidth int) error {
+	lines := []string{
+		fmt.Sprintf("Targeting %v against %v fleets, seed %v", r.Targeting, r.Placement, r.Seed),
+		summaryHeader(),
+		summaryLine("shots", r.Summary),
+		"",
+		"Histogram of shots to sink a fleet",
+	}
+
+	bins := Histogram(r.Shots, binWidth)
+
+	maxCount := 0
+	for _, b := range bins {
+		maxCount = max(maxCount, b.Count)
+	}
+
+	for _, b := range bins {
+		bar := strings.Repeat("#", int(math.Ceil(float64(b.Count)*50/float64(maxCount))))
+		lines = append(lines, fmt.Sprintf("%4v-%-4v %6v %v", b.From, b.To, b.Count, bar))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+
+	return err
+}
+
+func WriteTextComparison(w io.Writer, c Comparison) error {
+	lines := []string{
+		fmt.Sprintf("Baseline:  %v against %v fleets, seed %v", c.Baseline.Targeting, c.Baseline.Placement, c.Baseline.Seed),
+		fmt.Sprintf("Candidate: %v against %v fleets, seed %v", c.Candidate.Targeting, c.Candidate.Placement, c.Candidate.Seed),
+	}
+
+	if !c.Baseline.SameFleets(c.Candidate) {
+		lines = append(lines, "Warning: results were played against different fleets")
+	}
+
+	lines = append(lines,
+		summaryHeader(),
+		summaryLine("baseline", NewSummary(c.Baseline.Shots)),
+		summaryLine("candidate", NewSummary(c.Candidate.Shots)),
+		"",
+		fmt.Sprintf("Mean difference: %+.2f shots", c.MeanDiff),
+		fmt.Sprintf("%v: %v = %.1f, z = %.3f, p = %.4g", c.Test, c.StatisticName, c.Statistic, c.Z, c.P),
+		c.Verdict(),
+	)
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+
+	return err
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNewSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		shots  []int
+		median float64
+		p90    int
+	}{
+		{"odd count", []int{50, 10, 30, 20, 40}, 30, 50},
+		{"even count", []int{40, 10, 30, 20}, 25, 40},
+		{"ten fleets", []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, 5.5, 9},
+		{"eleven fleets", []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}, 6, 10},
+		{"single fleet", []int{17}, 17, 17},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewSummary(test.shots)
+
+			if s.Median != test.median {
+				t.Errorf("expected median %v, got %v", test.median, s.Median)
+			}
+
+			if s.P90 != test.p90 {
+				t.Errorf("expected p90 %v, got %v", test.p90, s.P90)
+			}
+
+			if s.Fleets != len(test.shots) || s.Min != slices.Min(test.shots) || s.Max != slices.Max(test.shots) {
+				t.Errorf("unexpected summary %+v", s)
+			}
+		})
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	tests := []struct {
+		name     string
+		shots    []int
+		width    int
+		expected []Bin
+	}{
+		{
+			"bins aligned to the width",
+			[]int{23, 30, 39, 40, 58},
+			10,
+			[]Bin{{20, 29, 1}, {30, 39, 2}, {40, 49, 1}, {50, 59, 1}},
+		},
+		{
+			"single value",
+			[]int{45, 45},
+			5,
+			[]Bin{{45, 49, 2}},
+		},
+		{
+			"unit width",
+			[]int{3, 5, 5},
+			1,
+			[]Bin{{3, 3, 1}, {4, 4, 0}, {5, 5, 2}},
+		},
+		{
+			"no fleets",
+			[]int{},
+			10,
+			[]Bin{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if bins := Histogram(test.shots, test.width); !slices.Equal(bins, test.expected) {
+				t.Fatalf("expected %v, got %v", test.expected, bins)
+			}
+		})
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		u, z float64
+	}{
+		// n1 = n2 = 3, sigma = sqrt(9/12 * 7)
+		{"separated samples", []int{1, 2, 3}, []int{4, 5, 6}, 0, -4.5 / math.Sqrt(5.25)},
+		{"swapped samples", []int{4, 5, 6}, []int{1, 2, 3}, 9, 4.5 / math.Sqrt(5.25)},
+		// three 2s share rank 3, sigma = sqrt(9/12 * (7 - 24/30))
+		{"tied samples", []int{1, 2, 2}, []int{2, 3, 4}, 1, -3.5 / math.Sqrt(4.65)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			u, z, p := MannWhitneyU(test.a, test.b)
+
+			if math.Abs(u-test.u) > 1e-9 || math.Abs(z-test.z) > 1e-9 {
+				t.Fatalf("expected U %v, z %v, got U %v, z %v", test.u, test.z, u, z)
+			}
+
+			if expected := math.Erfc(math.Abs(test.z) / math.Sqrt2); math.Abs(p-expected) > 1e-9 {
+				t.Fatalf("expected p %v, got %v", expected, p)
+			}
+		})
+	}
+
+	t.Run("all ties", func(t *testing.T) {
+		if _, z, p := MannWhitneyU([]int{7, 7, 7}, []int{7, 7}); z != 0 || p != 1 {
+			t.Fatalf("expected z 0, p 1, got z %v, p %v", z, p)
+		}
+	})
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []int
+		w, z float64
+	}{
+		// n = 4, mean 5, variance 4*5*9/24
+		{"all greater", []int{5, 7, 9, 10}, []int{4, 4, 4, 4}, 10, 5 / math.Sqrt(7.5)},
+		// differences 1, -2, 3, mean 3, variance 3*4*7/24
+		{"mixed differences", []int{2, 3, 8}, []int{1, 5, 5}, 4, 1 / math.Sqrt(3.5)},
+		// differences 1, -1, 2 rank 1.5, 1.5, 3, variance 3.5 - 6/48
+		{"tied differences", []int{2, 3, 4}, []int{1, 4, 2}, 4.5, 1.5 / math.Sqrt(3.375)},
+		{"equal pairs dropped", []int{2, 3, 8, 6, 6}, []int{1, 5, 5, 6, 6}, 4, 1 / math.Sqrt(3.5)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, z, p := WilcoxonSignedRank(test.a, test.b)
+
+			if math.Abs(w-test.w) > 1e-9 || math.Abs(z-test.z) > 1e-9 {
+				t.Fatalf("expected W %v, z %v, got W %v, z %v", test.w, test.z, w, z)
+			}
+
+			if expected := math.Erfc(math.Abs(test.z) / math.Sqrt2); math.Abs(p-expected) > 1e-9 {
+				t.Fatalf("expected p %v, got %v", expected, p)
+			}
+		})
+	}
+
+	t.Run("equal pairs", func(t *testing.T) {
+		if _, z, p := WilcoxonSignedRank([]int{3, 5, 8}, []int{3, 5, 8}); z != 0 || p != 1 {
+			t.Fatalf("expected z 0, p 1, got z %v, p %v", z, p)
+		}
+	})
+}
+
+func TestNewComparison(t *testing.T) {
+	baseline := Result{Placement: "random", Seed: 1, Shots: []int{50, 60, 70, 80, 90, 55, 65, 75}}
+	candidate := Result{Placement: "random", Seed: 1, Shots: []int{45, 58, 66, 79, 85, 50, 61, 70}}
+
+	if c := NewComparison(baseline, candidate, 0.05); c.Test != "Wilcoxon signed-rank" || !c.Significant || c.Z >= 0 {
+		t.Fatalf("expected the candidate to be significantly better on the same fleets, got %+v", c)
+	}
+
+	candidate.Seed = 2
+
+	if c := NewComparison(baseline, candidate, 0.05); c.Test != "Mann-Whitney U" || c.Significant {
+		t.Fatalf("expected no significant difference on different fleets, got %+v", c)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ConfigEnv names the environment variable pointing to the checker config file.
+const ConfigEnv = "BATTLESHIP_BOT_CHECK_CONFIG"
+
+type Config struct {
+	Addr        string        `yaml:"addr" toml:"addr" env:"BATTLESHIP_BOT_CHECK_ADDR" flag:"addr" usage:"address of the bot to check"`
+	Secret      string        `yaml:"secret" toml:"secret" env:"BATTLESHIP_BOT_CHECK_SECRET" flag:"secret" usage:"secret to sign requests with, if the bot requires signed requests" secret:"true"`
+	Timeout     time.Duration `yaml:"timeout" toml:"timeout" env:"BATTLESHIP_BOT_CHECK_TIMEOUT" flag:"timeout" usage:"deadline of every bot request"`
+	MaxLatency  time.Duration `yaml:"max_latency" toml:"max_latency" env:"BATTLESHIP_BOT_CHECK_MAX_LATENCY" flag:"max-latency" usage:"maximum 95th percentile of GetStrike latency"`
+	Fields      int           `yaml:"fields" toml:"fields" env:"BATTLESHIP_BOT_CHECK_FIELDS" flag:"fields" usage:"number of game ids to request fields for"`
+	Samples     int           `yaml:"samples" toml:"samples" env:"BATTLESHIP_BOT_CHECK_SAMPLES" flag:"samples" usage:"number of GetStrike requests to measure latency with"`
+	Games       int           `yaml:"games" toml:"games" env:"BATTLESHIP_BOT_CHECK_GAMES" flag:"games" usage:"number of full games to play against a random opponent"`
+	Format      string        `yaml:"format" toml:"format" env:"BATTLESHIP_BOT_CHECK_FORMAT" flag:"format" usage:"report format: text or json"`
+	TLS         bool          `yaml:"tls" toml:"tls" env:"BATTLESHIP_BOT_CHECK_TLS" flag:"tls" usage:"connect to the bot over TLS"`
+	TLSCAFile   string        `yaml:"tls_ca_file" toml:"tls_ca_file" env:"BATTLESHIP_BOT_CHECK_TLS_CA_FILE" flag:"tls-ca-file" usage:"CA certificates to verify the bot certificate with, instead of system ones"`
+	TLSCertFile string        `yaml:"tls_cert_file" toml:"tls_cert_file" env:"BATTLESHIP_BOT_CHECK_TLS_CERT_FILE" flag:"tls-cert-file" usage:"client certificate presented to bots requiring mutual TLS"`
+	TLSKeyFile  string        `yaml:"tls_key_file" toml:"tls_key_file" env:"BATTLESHIP_BOT_CHECK_TLS_KEY_FILE" flag:"tls-key-file" usage:"client certificate key"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.Addr = "localhost:6968"
+	c.Timeout = time.Second
+	c.MaxLatency = 500 * time.Millisecond
+	c.Fields = 5
+	c.Samples = 20
+	c.Games = 4
+	c.Format = "text"
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if c.Addr == "" {
+		errs = append(errs, errors.New("addr must not be empty"))
+	}
+
+	if c.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout must be positive"))
+	}
+
+	if c.MaxLatency <= 0 {
+		errs = append(errs, errors.New("max_latency must be positive"))
+	}
+
+	if c.Fields < 1 {
+		errs = append(errs, errors.New("fields must be positive"))
+	}
+
+	if c.Samples < 1 {
+		errs = append(errs, errors.New("samples must be positive"))
+	}
+
+	if c.Games < 0 {
+		errs = append(errs, errors.New("games must not be negative"))
+	}
+
+	if c.Format != "text" && c.Format != "json" {
+		errs = append(errs, fmt.Errorf("format: unknown format %q, expected text or json", c.Format))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("tls_cert_file and tls_key_file must be set together"))
+	}
+
+	if !c.TLS && (c.TLSCAFile != "" || c.TLSCertFile != "") {
+		errs = append(errs, errors.New("tls_ca_file, tls_cert_file and tls_key_file require tls"))
+	}
+
+	if _, err := c.DialOptions(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DialOptions returns options of the connection to the bot.
+func (c Config) DialOptions() ([]grpc.DialOption, error) {
+	if !c.TLS {
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+	}
+
+	config, err := core.NewClientTLSConfig(c.TLSCAFile, c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}, nil
+}
+
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	opts, err := config.DialOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure connection: %v\n", err)
+		os.Exit(2)
+	}
+
+	conn, err := grpc.Dial(config.Addr, opts...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to %v: %v\n", config.Addr, err)
+		os.Exit(2)
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report := NewChecker(pbbot.NewBattleshipBotServiceClient(conn), config).Run(ctx)
+
+	if config.Format == "json" {
+		err = WriteJsonReport(os.Stdout, report)
+	} else {
+		err = WriteTextReport(os.Stdout, report)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+		os.Exit(2)
+	}
+
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+func WriteTextReport(w io.Writer, r Report) error {
+	counts := make(map[CheckStatus]int)
+
+	for _, c := range r.Checks {
+		counts[c.Status] += 1
+
+		if _, err := fmt.Fprintf(w, "%-4v  %-28v %8v  %v\n", strings.ToUpper(string(c.Status)), c.Name, time.Duration(c.DurationMs)*time.Millisecond, c.Message); err != nil {
+			return err
+		}
+	}
+
+	result := "PASSED"
+	if !r.Passed {
+		result = "FAILED"
+	}
+
+	_, err := fmt.Fprintf(w, "\n%v %v: %v passed, %v warnings, %v failed\n", r.Addr, result, counts[CheckPass], counts[CheckWarn], counts[CheckFail])
+
+	return err
+}
+
+func WriteJsonReport(w io.Writer, r Report) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(r)
+}
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+
+	bottest "github.com/mtratsiuk/battleship/battleship-go-bot-test"
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	strategy "github.com/mtratsiuk/battleship/battleship-go-strategy"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	// CheckWarn is reported for behaviour the server tolerates, but which
+	// likely isn't intended, it doesn't fail the report.
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+type CheckResult struct {
+	Name       string      `json:"name"`
+	Status     CheckStatus `json:"status"`
+	Message    string      `json:"message"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+type Report struct {
+	Addr   string        `json:"addr"`
+	Passed bool          `json:"passed"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Checker runs the conformance suite against a bot.
+type Checker struct {
+	client pbbot.BattleshipBotServiceClient
+	config Config
+	rng    *rand.Rand
+	// latencies of successful GetStrike requests
+	latencies []time.Duration
+}
+
+func NewChecker(client pbbot.BattleshipBotServiceClient, config Config) *Checker {
+	c := &Checker{}
+	c.client = client
+	c.config = config
+	c.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	c.latencies = make([]time.Duration, 0)
+
+	return c
+}
+
+type check struct {
+	name string
+	run  func(ctx context.Context) (CheckStatus, string)
+}
+
+func (c *Checker) Run(ctx context.Context) Report {
+	checks := []check{
+		{"get_info", c.checkInfo},
+		{"get_field", c.checkField},
+		{"get_strike_empty_board", c.checkStrikeEmptyBoard},
+		{"get_strike_mid_game", c.checkStrikeMidGame},
+		{"get_strike_nearly_full_board", c.checkStrikeNearlyFullBoard},
+		{"get_field_malformed", c.checkFieldMalformed},
+		{"get_strike_malformed", c.checkStrikeMalformed},
+		{"get_strike_latency", c.checkStrikeLatency},
+		{"games", c.checkGames},
+	}
+
+	report := Report{}
+	report.Addr = c.config.Addr
+	report.Passed = true
+	report.Checks = make([]CheckResult, 0, len(checks))
+
+	for _, check := range checks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		start := time.Now()
+		s, message := check.run(ctx)
+
+		report.Checks = append(report.Checks, CheckResult{Name: check.name, Status: s, Message: message, DurationMs: time.Since(start).Milliseconds()})
+
+		if s == CheckFail {
+			report.Passed = false
+		}
+	}
+
+	if ctx.Err() != nil {
+		report.Passed = false
+	}
+
+	return report
+}
+
+// context signs the request, if the secret is set, and applies the timeout.
+func (c *Checker) context(ctx context.Context, method, gameId string, request proto.Message) (context.Context, context.CancelFunc, error) {
+	if c.config.Secret != "" {
+		md, err := core.NewSignatureMetadata(c.config.Secret, method, gameId, request, time.Now())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+
+	return ctx, cancel, nil
+}
+
+func (c *Checker) getField(ctx context.Context, request *pbbot.GetFieldRequest) (*pbbot.GetFieldResponse, error) {
+	ctx, cancel, err := c.context(ctx, pbbot.BattleshipBotService_GetField_FullMethodName, request.GameId, request)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	return c.client.GetField(ctx, request)
+}
+
+func (c *Checker) getStrike(ctx context.Context, request *pbbot.GetStrikeRequest) (*pbbot.GetStrikeResponse, error) {
+	ctx, cancel, err := c.context(ctx, pbbot.BattleshipBotService_GetStrike_FullMethodName, request.GameId, request)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	start := time.Now()
+	resp, err := c.client.GetStrike(ctx, request)
+
+	if err == nil {
+		c.latencies = append(c.latencies, time.Since(start))
+	}
+
+	return resp, err
+}
+
+func (c *Checker) checkInfo(ctx context.Context) (CheckStatus, string) {
+	request := &pbbot.GetInfoRequest{}
+
+	ctx, cancel, err := c.context(ctx, pbbot.BattleshipBotService_GetInfo_FullMethodName, "", request)
+	if err != nil {
+		return CheckFail, fmt.Sprintf("failed to sign GetInfo: %v", err)
+	}
+	defer cancel()
+
+	resp, err := c.client.GetInfo(ctx, request)
+	if status.Code(err) == codes.Unimplemented {
+		return CheckWarn, fmt.Sprintf("GetInfo is not implemented, the bot is assumed to play %v/%v", core.BattleshipDefaultRuleset, core.BattleshipDefaultVariant)
+	}
+	if err != nil {
+		return CheckFail, fmt.Sprintf("GetInfo failed: %v", err)
+	}
+
+	info := resp.GetInfo()
+	if info == nil {
+		return CheckFail, "GetInfo returned no info"
+	}
+
+	warnings := make([]string, 0)
+
+	if info.ProtocolVersion != core.BattleshipProtocolVersion {
+		warnings = append(warnings, fmt.Sprintf("protocol version is %q, expected %q", info.ProtocolVersion, core.BattleshipProtocolVersion))
+	}
+
+	if !slices.Contains(info.Rulesets, core.BattleshipDefaultRuleset) || !slices.Contains(info.Variants, core.BattleshipDefaultVariant) {
+		warnings = append(warnings, fmt.Sprintf("%v/%v isn't supported, the bot will only play bots supporting %v/%v", core.BattleshipDefaultRuleset, core.BattleshipDefaultVariant, info.Rulesets, info.Variants))
+	}
+
+	if len(warnings) > 0 {
+		return CheckWarn, strings.Join(warnings, "; ")
+	}
+
+	return CheckPass, fmt.Sprintf("%v %v by %q, protocol %v", info.Name, info.Version, info.Author, info.ProtocolVersion)
+}
+
+func (c *Checker) checkField(ctx context.Context) (CheckStatus, string) {
+	failures := make([]string, 0)
+
+	for i := 0; i < c.config.Fields; i += 1 {
+		gameId := core.NewId()
+
+		resp, err := c.getField(ctx, &pbbot.GetFieldRequest{GameId: gameId})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("game %v: GetField failed: %v", gameId, err))
+			continue
+		}
+
+		f, err := core.NewBattleshipFieldFromProto(&pbcore.BattleshipFieldProto{Field: resp.Field})
+		if err == nil {
+			err = f.Validate()
+		}
+
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("game %v: invalid field: %v", gameId, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return CheckFail, strings.Join(failures, "; ")
+	}
+
+	return CheckPass, fmt.Sprintf("%v valid fields", c.config.Fields)
+}
+
+func (c *Checker) checkStrikeEmptyBoard(ctx context.Context) (CheckStatus, string) {
+	other := core.NewBattleshipField()
+
+	pos, err := c.strike(ctx, c.ownField(0), other)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+
+	return CheckPass, fmt.Sprintf("struck %v", pos)
+}
+
+func (c *Checker) checkStrikeMidGame(ctx context.Context) (CheckStatus, string) {
+	for i := 0; i < 5; i += 1 {
+		other := c.otherField(20 + c.rng.Intn(40))
+
+		if _, err := c.strike(ctx, c.ownField(10), other); err != nil {
+			return CheckFail, fmt.Sprintf("%v, after %v hits and %v misses", err, len(other.Hits.Items()), len(other.Misses.Items()))
+		}
+	}
+
+	return CheckPass, "struck unknown positions of 5 games in progress"
+}
+
+// checkStrikeNearlyFullBoard leaves a single position of the opponent's
+// fleet unknown, it is the only valid strike.
+func (c *Checker) checkStrikeNearlyFullBoard(ctx context.Context) (CheckStatus, string) {
+	other := c.otherField(core.BattleshipFieldSize*core.BattleshipFieldSize - 1)
+
+	last := core.BattleshipPos{}
+	for y := 0; y < core.BattleshipFieldSize; y += 1 {
+		for x := 0; x < core.BattleshipFieldSize; x += 1 {
+			pos := core.BattleshipPos{X: x, Y: y}
+
+			if !other.Hits.Has(pos) && !other.Misses.Has(pos) {
+				last = pos
+			}
+		}
+	}
+
+	pos, err := c.strike(ctx, c.ownField(16), other)
+	if err != nil {
+		return CheckFail, err.Error()
+	}
+
+	if pos != last {
+		return CheckFail, fmt.Sprintf("struck %v, the only unknown position is %v", pos, last)
+	}
+
+	return CheckPass, fmt.Sprintf("struck the only unknown position %v", pos)
+}
+
+type malformedCase struct {
+	name    string
+	request func(r *pbbot.GetStrikeRequest)
+}
+
+func (c *Checker) checkFieldMalformed(ctx context.Context) (CheckStatus, string) {
+	_, err := c.getField(ctx, &pbbot.GetFieldRequest{GameId: ""})
+
+	return expectInvalidArgument([]string{"empty game id"}, []error{err})
+}
+
+func (c *Checker) checkStrikeMalformed(ctx context.Context) (CheckStatus, string) {
+	cases := []malformedCase{
+		{"empty game id", func(r *pbbot.GetStrikeRequest) { r.GameId = "" }},
+		{"missing own field", func(r *pbbot.GetStrikeRequest) { r.OwnField = nil }},
+		{"missing other field", func(r *pbbot.GetStrikeRequest) { r.OtherField = nil }},
+		{"own field without ships", func(r *pbbot.GetStrikeRequest) {
+			empty := core.NewBattleshipField()
+			r.OwnField.Field = empty.ToProto().Field
+		}},
+		{"own field of wrong size", func(r *pbbot.GetStrikeRequest) { r.OwnField.Field = r.OwnField.Field[:5] }},
+		{"out of range hit", func(r *pbbot.GetStrikeRequest) {
+			r.OtherField.Hits = append(r.OtherField.Hits, &pbcore.BattleshipPosProto{X: core.BattleshipFieldSize, Y: 0})
+		}},
+		{"negative miss", func(r *pbbot.GetStrikeRequest) {
+			r.OtherField.Misses = append(r.OtherField.Misses, &pbcore.BattleshipPosProto{X: 0, Y: -1})
+		}},
+		{"position struck twice", func(r *pbbot.GetStrikeRequest) {
+			pos := &pbcore.BattleshipPosProto{X: 0, Y: 0}
+			r.OtherField.Misses = append(r.OtherField.Misses, pos, pos)
+		}},
+	}
+
+	names := make([]string, 0, len(cases))
+	errs := make([]error, 0, len(cases))
+
+	for _, mc := range cases {
+		request := &pbbot.GetStrikeRequest{GameId: core.NewId()}
+		own := c.ownField(0)
+		request.OwnField = own.ToProto()
+		other := c.otherField(10)
+		request.OtherField = other.ToOtherFieldProto()
+
+		mc.request(request)
+
+		_, err := c.getStrike(ctx, request)
+
+		names = append(names, mc.name)
+		errs = append(errs, err)
+	}
+
+	return expectInvalidArgument(names, errs)
+}
+
+// expectInvalidArgument fails if a malformed request succeeded and warns if
+// it was rejected with a code other than InvalidArgument.
+func expectInvalidArgument(names []string, errs []error) (CheckStatus, string) {
+	accepted := make([]string, 0)
+	otherCodes := make([]string, 0)
+
+	for i, err := range errs {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+		case codes.OK:
+			accepted = append(accepted, names[i])
+		default:
+			otherCodes = append(otherCodes, fmt.Sprintf("%v: %v", names[i], status.Code(err)))
+		}
+	}
+
+	if len(accepted) > 0 {
+		return CheckFail, fmt.Sprintf("accepted malformed requests: %v", strings.Join(accepted, ", "))
+	}
+
+	if len(otherCodes) > 0 {
+		return CheckWarn, fmt.Sprintf("expected InvalidArgument, got %v", strings.Join(otherCodes, ", "))
+	}
+
+	return CheckPass, fmt.Sprintf("rejected %v malformed requests with InvalidArgument", len(names))
+}
+
+func (c *Checker) checkStrikeLatency(ctx context.Context) (CheckStatus, string) {
+	for len(c.latencies) < c.config.Samples {
+		if _, err := c.strike(ctx, c.ownField(c.rng.Intn(16)), c.otherField(c.rng.Intn(80))); err != nil {
+			return CheckFail, err.Error()
+		}
+	}
+
+	latencies := slices.Clone(c.latencies)
+	slices.Sort(latencies)
+
+	p50 := latencies[len(latencies)*50/100]
+	p95 := latencies[len(latencies)*95/100]
+	max := latencies[len(latencies)-1]
+
+	message := fmt.Sprintf("p50 %v, p95 %v, max %v over %v requests", p50, p95, max, len(latencies))
+
+	if p95 > c.config.MaxLatency {
+		return CheckFail, fmt.Sprintf("%v, p95 exceeds %v", message, c.config.MaxLatency)
+	}
+
+	if max > c.config.MaxLatency {
+		return CheckWarn, fmt.Sprintf("%v, max exceeds %v", message, c.config.MaxLatency)
+	}
+
+	return CheckPass, message
+}
+
+func (c *Checker) checkGames(ctx context.Context) (CheckStatus, string) {
+	options := bottest.NewOptions()
+	options.Games = c.config.Games
+	options.Opponent = bottest.RandomOpponent(c.rng.Int63())
+	options.Timeout = c.config.Timeout
+	options.Secret = c.config.Secret
+
+	report := bottest.PlayGames(ctx, c.client, options)
+
+	if failures := report.Failures(); len(failures) > 0 {
+		return CheckFail, strings.Join(failures, "; ")
+	}
+
+	return CheckPass, fmt.Sprintf("won %v of %v games against a random opponent", report.Wins(), len(report.Games))
+}
+
+// strike requests a strike and checks it is in range and wasn't struck yet.
+func (c *Checker) strike(ctx context.Context, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	request := &pbbot.GetStrikeRequest{GameId: core.NewId(), OwnField: own.ToProto(), OtherField: other.ToOtherFieldProto()}
+
+	resp, err := c.getStrike(ctx, request)
+	if err != nil {
+		return core.BattleshipPos{}, fmt.Errorf("GetStrike failed: %w", err)
+	}
+
+	if resp.GetPos() == nil {
+		return core.BattleshipPos{}, fmt.Errorf("GetStrike returned no position")
+	}
+
+	pos := core.NewBattleshipPosFromProto(resp.Pos)
+
+	if !pos.IsInBounds() {
+		return pos, fmt.Errorf("position %v is out of range", pos)
+	}
+
+	if other.Hits.Has(pos) || other.Misses.Has(pos) {
+		return pos, fmt.Errorf("position %v was already struck", pos)
+	}
+
+	return pos, nil
+}
+
+// ownField returns a random fleet struck at the given number of random
+// positions, without sinking it.
+func (c *Checker) ownField(strikes int) core.BattleshipField {
+	f, _ := strategy.RandomPlacement{}.Place(context.Background(), c.rng)
+
+	for _, pos := range c.strikePositions(&f, strikes) {
+		f.Strike(pos)
+	}
+
+	return f
+}
+
+// otherField returns hits and misses of a random fleet struck at the given
+// number of random positions, without sinking it.
+func (c *Checker) otherField(strikes int) core.BattleshipField {
+	fleet, _ := strategy.RandomPlacement{}.Place(context.Background(), c.rng)
+
+	other := core.NewBattleshipField()
+
+	for _, pos := range c.strikePositions(&fleet, strikes) {
+		if fleet.Field[pos.Y][pos.X].IsEmpty() {
+			other.Misses.Add(pos)
+		} else {
+			other.Hits.Add(pos)
+		}
+	}
+
+	return other
+}
+
+// strikePositions returns random positions of the field, leaving one ship
+// position unstruck so the fleet isn't sunk.
+func (c *Checker) strikePositions(f *core.BattleshipField, count int) []core.BattleshipPos {
+	ps := make([]core.BattleshipPos, 0, core.BattleshipFieldSize*core.BattleshipFieldSize)
+	var spared *core.BattleshipPos
+
+	for _, i := range c.rng.Perm(core.BattleshipFieldSize * core.BattleshipFieldSize) {
+		pos := core.BattleshipPos{X: i % core.BattleshipFieldSize, Y: i / core.BattleshipFieldSize}
+
+		if spared == nil && !f.Field[pos.Y][pos.X].IsEmpty() {
+			spared = &pos
+			continue
+		}
+
+		ps = append(ps, pos)
+	}
+
+	return ps[:min(count, len(ps))]
+}
@@ -1,213 +1,128 @@
 package main
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"log"
 	"log/slog"
-	"math/rand"
-	"net"
-	"os"
 	"time"
 
+	botsdk "github.com/mtratsiuk/battleship/battleship-go-bot-sdk"
 	core "github.com/mtratsiuk/battleship/battleship-go-core"
-	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
-	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
-	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
-	"golang.org/x/exp/maps"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
-)
-
-func main() {
-	botServer := NewBotServer()
-
-	client, close, err := core.NewBattleshipServerServiceClient()
-	if err != nil {
-		botServer.logger.Error(fmt.Sprintf("failed to connect to the bot runner gRPC server: %v", err))
-		os.Exit(1)
-	}
-	defer close()
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
-
-	request := &pbserver.JoinLobbyRequest{
-		Addr: botServer.config.externalAddr,
-		Name: botServer.config.botName,
-	}
-
-	botServer.logger.Info(fmt.Sprintf("joining lobby with a delay... %v", request))
-
-	time.Sleep(time.Second * 5)
-	resp, err := (*client).JoinLobby(ctx, request)
-	if err != nil {
-		botServer.logger.Error(fmt.Sprintf("failed to join lobby: %v", err))
-		os.Exit(1)
-	}
-	botServer.logger.Info(fmt.Sprintf("joined lobby: %v", resp))
-
-	gprcUrl := fmt.Sprintf("%v:%v", botServer.config.grpcServerHost, botServer.config.grpcServerPort)
-	lis, err := net.Listen("tcp", gprcUrl)
-	if err != nil {
-		log.Panicf("failed to listen: %v", err)
-	}
-
-	grpcServer := grpc.NewServer()
-	defer grpcServer.GracefulStop()
-
-	pbbot.RegisterBattleshipBotServiceServer(grpcServer, &botServer)
-	reflection.Register(grpcServer)
-
-	botServer.logger.Info(fmt.Sprintf("Starting gRPC server at: %v", gprcUrl))
-	if err := grpcServer.Serve(lis); err != nil {
-		botServer.logger.Error(fmt.Sprintf("failed to servce gRPC: %v", err))
-		os.Exit(1)
-	}
-}
-
-type CtxKey string
-
-const (
-	CtxKeyMethod = CtxKey("Method")
-	CtxKeyGameId = CtxKey("GameId")
+	_ "github.com/mtratsiuk/battleship/battleship-go-strategy"
 )
 
 type Config struct {
-	grpcServerHost string
-	grpcServerPort string
-	externalAddr   string
-	botName        string
+	botsdk.Config `yaml:",inline"`
+
+	Placement string `yaml:"placement" toml:"placement" env:"BATTLESHIP_BOT_GO_PLACEMENT" flag:"placement" usage:"placement strategy name"`
+	Targeting string `yaml:"targeting" toml:"targeting" env:"BATTLESHIP_BOT_GO_TARGETING" flag:"targeting" usage:"targeting strategy name"`
+	// PlacementParams and TargetingParams set parameters of tunable
+	// strategies, e.g. ones found by battleship-tune.
+	PlacementParams     map[string]float64 `yaml:"placement_params" toml:"placement_params"`
+	TargetingParams     map[string]float64 `yaml:"targeting_params" toml:"targeting_params"`
+	Bots                []botsdk.BotSpec   `yaml:"bots" toml:"bots"`
+	OpponentStats       string             `yaml:"opponent_stats" toml:"opponent_stats" env:"BATTLESHIP_BOT_GO_OPPONENT_STATS" flag:"opponent-stats" usage:"file to keep statistics of opponents in, opponents aren't modeled if empty"`
+	OpponentDecay       float64            `yaml:"opponent_decay" toml:"opponent_decay" env:"BATTLESHIP_BOT_GO_OPPONENT_DECAY" flag:"opponent-decay" usage:"weight of previous games of an opponent relative to the next one, from 0 to 1"`
+	OpponentGameTimeout time.Duration      `yaml:"opponent_game_timeout" toml:"opponent_game_timeout" env:"BATTLESHIP_BOT_GO_OPPONENT_GAME_TIMEOUT" flag:"opponent-game-timeout" usage:"time without strike requests after which a game not played over PlayGame is recorded as over"`
+	OpponentArchive     string             `yaml:"opponent_archive" toml:"opponent_archive" env:"BATTLESHIP_BOT_GO_OPPONENT_ARCHIVE" flag:"opponent-archive" usage:"directory of GameProto JSON files to learn opponents from at startup, e.g. battleship-tournament logs"`
 }
 
 func NewConfig() Config {
 	c := Config{}
-
-	c.grpcServerHost = core.EnvOr("BATTLESHIP_BOT_GO_GRPC_HOST", "0.0.0.0")
-	c.grpcServerPort = core.EnvOr("BATTLESHIP_BOT_GO_GRPC_PORT", "6968")
-	c.externalAddr = core.EnvOr("BATTLESHIP_BOT_GO_EXTERNAL_ADDR", "0.0.0.0:6968")
-	c.botName = core.EnvOr("BATTLESHIP_BOT_GO_NAME", "Go Bot")
+	c.Config = botsdk.NewConfig()
+	c.Placement = "random"
+	c.Targeting = "random"
+	c.PlacementParams = make(map[string]float64)
+	c.TargetingParams = make(map[string]float64)
+	c.Bots = make([]botsdk.BotSpec, 0)
+	c.OpponentStats = ""
+	c.OpponentDecay = 0.9
+	c.OpponentGameTimeout = time.Minute
+	c.OpponentArchive = ""
 
 	return c
 }
 
-type BotServer struct {
-	pbbot.UnimplementedBattleshipBotServiceServer
-
-	config Config
-	logger *slog.Logger
-}
-
-func NewBotServer() BotServer {
-	b := BotServer{}
-	b.config = NewConfig()
-	b.logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+func (c Config) Validate() error {
+	errs := []error{c.Config.Validate(), botsdk.ValidateBotSpecs(c.Bots)}
 
-	return b
-}
-
-func (b *BotServer) GetField(ctx context.Context, request *pbbot.GetFieldRequest) (*pbbot.GetFieldResponse, error) {
-	ctx = context.WithValue(ctx, CtxKeyMethod, "GetField")
-	ctx = context.WithValue(ctx, CtxKeyGameId, request.GameId)
-	b.logger.InfoContext(ctx, "Received GetField request")
-
-	f := core.NewBattleshipField()
-
-	for _, ship := range core.BattleshipKinds {
-		for {
-			if ok := tryToAddShip(&f, ship); ok {
-				break
-			}
-		}
+	if _, err := core.NewPlacementStrategyWithParams(c.Placement, c.PlacementParams); err != nil {
+		errs = append(errs, fmt.Errorf("placement: %w", err))
 	}
 
-	resp := pbbot.GetFieldResponse{Field: f.ToProto().Field}
-
-	return &resp, nil
-}
+	if _, err := core.NewTargetingStrategyWithParams(c.Targeting, c.TargetingParams); err != nil {
+		errs = append(errs, fmt.Errorf("targeting: %w", err))
+	}
 
-func (b *BotServer) GetStrike(ctx context.Context, request *pbbot.GetStrikeRequest) (*pbbot.GetStrikeResponse, error) {
-	ctx = context.WithValue(ctx, CtxKeyMethod, "GetStrike")
-	ctx = context.WithValue(ctx, CtxKeyGameId, request.GameId)
-	b.logger.InfoContext(ctx, "Received GetStrike request")
+	if c.OpponentDecay <= 0 || c.OpponentDecay > 1 {
+		errs = append(errs, errors.New("opponent_decay must be within (0, 1]"))
+	}
 
-	x, y, err := findStrikePos(request)
+	if c.OpponentGameTimeout <= 0 {
+		errs = append(errs, errors.New("opponent_game_timeout must be positive"))
+	}
 
-	if err != nil {
-		b.logger.WarnContext(ctx, err.Error())
-		return nil, err
+	if c.OpponentStats != "" && len(c.Bots) > 0 {
+		errs = append(errs, errors.New("opponent_stats is not supported with bots"))
 	}
 
-	resp := &pbbot.GetStrikeResponse{Pos: &pbcore.BattleshipPosProto{X: int32(x), Y: int32(y)}}
+	if c.OpponentArchive != "" && c.OpponentStats == "" {
+		errs = append(errs, errors.New("opponent_archive requires opponent_stats"))
+	}
 
-	return resp, nil
+	return errors.Join(errs...)
 }
 
-func tryToAddShip(f *core.BattleshipField, ship core.BattleshipKind) bool {
-	shipSize := ship.Size()
-	horizontal := true
-	if rand.Intn(2) == 1 {
-		horizontal = false
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, botsdk.ConfigEnv)
+
+	if len(config.Bots) > 0 {
+		botsdk.MainFunc(config.Config, func(g *botsdk.Group) error {
+			return botsdk.AddBots(g, config.Config, config.Bots)
+		})
+		return
 	}
 
-	curShip := make([]core.BattleshipPos, 0)
-	x := rand.Intn(core.BattleshipFieldSize)
-	y := rand.Intn(core.BattleshipFieldSize)
-
-	if horizontal {
-		x = min(x, core.BattleshipFieldSize-shipSize)
-	} else {
-		y = min(y, core.BattleshipFieldSize-shipSize)
+	placer, shooter, err := botsdk.NewStrategiesWithParams(config.Placement, config.PlacementParams, config.Targeting, config.TargetingParams)
+	if err != nil {
+		log.Panicln(err)
 	}
 
-	for d := 0; d < shipSize; d += 1 {
-		cx := x
-		cy := y
-
-		if horizontal {
-			cx += d
-		} else {
-			cy += d
+	botsdk.MainFunc(config.Config, func(g *botsdk.Group) error {
+		if config.OpponentStats == "" {
+			_, err := g.Add(config.Config, placer, shooter)
+			return err
 		}
 
-		if f.Field[cy][cx].Kind != core.BattleshipTileKindEmpty {
-			return false
+		model, err := newOpponentModel(config, g.Logger())
+		if err != nil {
+			return err
 		}
 
-		curShip = append(curShip, core.BattleshipPos{X: cx, Y: cy})
-	}
-
-	for _, pos := range curShip {
-		f.Field[pos.Y][pos.X] = core.NewBattleshipTile(ship)
-	}
-
-	return true
+		_, err = g.Add(config.Config, ModelingPlacer{placer.Strategy, model}, ModelingShooter{shooter.Strategy, model})
+		return err
+	})
 }
 
-func findStrikePos(request *pbbot.GetStrikeRequest) (int, int, error) {
-	ps := make(map[int]struct{}, 0)
+// newOpponentModel loads saved opponent statistics and records games of the
+// archive, if configured.
+func newOpponentModel(config Config, logger *slog.Logger) (*OpponentModel, error) {
+	model := NewOpponentModel(config.OpponentStats, config.OpponentDecay, config.OpponentGameTimeout, logger)
 
-	for y := 0; y < core.BattleshipFieldSize; y += 1 {
-		for x := 0; x < core.BattleshipFieldSize; x += 1 {
-			key := x*10 + y
-			ps[key] = struct{}{}
-		}
-	}
-
-	for _, h := range request.OtherField.Hits {
-		delete(ps, int(h.X*10+h.Y))
+	if err := model.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load opponent statistics: %w", err)
 	}
 
-	for _, m := range request.OtherField.Misses {
-		delete(ps, int(m.X*10+m.Y))
-	}
-
-	toStrike := maps.Keys(ps)
+	if config.OpponentArchive != "" {
+		imported, err := model.ImportArchive(config.OpponentArchive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import opponent archive: %w", err)
+		}
 
-	if len(toStrike) == 0 {
-		return 0, 0, fmt.Errorf("nowhere left to strike")
+		logger.Info(fmt.Sprintf("Learned opponents from %v new games of %v", imported, config.OpponentArchive))
 	}
 
-	strike := toStrike[rand.Intn(len(toStrike))]
-	return strike / 10, strike % 10, nil
+	return model, nil
 }
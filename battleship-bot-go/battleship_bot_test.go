@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	botsdk "github.com/mtratsiuk/battleship/battleship-go-bot-sdk"
+	bottest "github.com/mtratsiuk/battleship/battleship-go-bot-test"
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+func TestStrategies(t *testing.T) {
+	for _, placement := range core.PlacementStrategyNames() {
+		for _, targeting := range core.TargetingStrategyNames() {
+			t.Run(placement+"/"+targeting, func(t *testing.T) {
+				placer, shooter, err := botsdk.NewStrategies(placement, targeting)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				bottest.Run(t, placer, shooter)
+			})
+		}
+	}
+}
+
+func TestOpponentModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "opponents.json")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.WithValue(context.Background(), botsdk.CtxKeyOpponentName, "Opponent")
+
+	placement, _ := core.NewPlacementStrategy("random")
+	targeting, _ := core.NewTargetingStrategy("density")
+
+	model := NewOpponentModel(path, 0.5, time.Minute, logger)
+	placer := ModelingPlacer{placement, model}
+	shooter := ModelingShooter{targeting, model}
+
+	for game := 0; game < 2; game += 1 {
+		gameId := fmt.Sprintf("game-%v", game)
+
+		own, err := placer.Place(ctx, gameId)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		own.Strike(core.BattleshipPos{X: 9, Y: 9})
+
+		if _, err := shooter.Shoot(ctx, gameId, own, core.NewBattleshipField()); err != nil {
+			t.Fatal(err)
+		}
+
+		fleet, _ := placement.Place(ctx, botsdk.NewRand())
+		shooter.GameOver(ctx, gameId, true, &fleet)
+	}
+
+	loaded := NewOpponentModel(path, 0.5, time.Minute, logger)
+	if err := loaded.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, ok := loaded.Stats("Opponent")
+	if !ok {
+		t.Fatal("expected statistics of the opponent to be saved")
+	}
+
+	// the second game weighs 1, the first one is decayed by half
+	if stats.Layouts != 1.5 || stats.Games != 1.5 || stats.Earliness[9][9] != 1.5 {
+		t.Fatalf("unexpected statistics: layouts %v, games %v, earliness %v", stats.Layouts, stats.Games, stats.Earliness[9][9])
+	}
+}
+
+// observe makes shots at the field of the bot, every request is observed
+// with the shots made since the previous one.
+func observe(model *OpponentModel, gameId string, requests ...[]core.BattleshipPos) {
+	own := core.NewBattleshipField()
+
+	for _, shots := range requests {
+		for _, pos := range shots {
+			own.Strike(pos)
+		}
+
+		model.ObserveShots(gameId, own)
+	}
+}
+
+func TestOpponentModelEarliness(t *testing.T) {
+	pos := func(x, y int) core.BattleshipPos { return core.BattleshipPos{X: x, Y: y} }
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name      string
+		play      func(model *OpponentModel)
+		earliness map[core.BattleshipPos]float64
+	}{
+		{
+			"unary requests",
+			func(model *OpponentModel) {
+				// the last request follows a missed one, its new shots are
+				// recorded in row-major order
+				observe(model, "game", []core.BattleshipPos{pos(5, 5)}, []core.BattleshipPos{pos(0, 0)}, []core.BattleshipPos{pos(9, 1)}, []core.BattleshipPos{pos(1, 7), pos(2, 2)})
+			},
+			map[core.BattleshipPos]float64{pos(5, 5): 1, pos(0, 0): 0.99, pos(9, 1): 0.98, pos(2, 2): 0.97, pos(1, 7): 0.96},
+		},
+		{
+			"retried request",
+			func(model *OpponentModel) {
+				observe(model, "game", []core.BattleshipPos{pos(5, 5)}, []core.BattleshipPos{}, []core.BattleshipPos{pos(0, 0)})
+			},
+			map[core.BattleshipPos]float64{pos(5, 5): 1, pos(0, 0): 0.99},
+		},
+		{
+			"streamed strike outcomes",
+			func(model *OpponentModel) {
+				for _, p := range []core.BattleshipPos{pos(9, 9), pos(0, 0), pos(4, 4)} {
+					model.ObserveShot("game", p)
+				}
+
+				// fields of a streamed game don't add shots already recorded
+				observe(model, "game", []core.BattleshipPos{pos(0, 0), pos(4, 4), pos(9, 9)})
+			},
+			map[core.BattleshipPos]float64{pos(9, 9): 1, pos(0, 0): 0.99, pos(4, 4): 0.98},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			model := NewOpponentModel(filepath.Join(t.TempDir(), "opponents.json"), 0.5, time.Minute, logger)
+			model.StartGame("game", "Opponent")
+			test.play(model)
+			model.EndGame("game", "Opponent", nil)
+
+			stats, _ := model.Stats("Opponent")
+			if stats.Games != 1 {
+				t.Fatalf("expected a single game, got %v", stats.Games)
+			}
+
+			for y := 0; y < core.BattleshipFieldSize; y += 1 {
+				for x := 0; x < core.BattleshipFieldSize; x += 1 {
+					if expected := test.earliness[pos(x, y)]; math.Abs(stats.Earliness[y][x]-expected) > 1e-9 {
+						t.Errorf("expected earliness %v at %v, got %v", expected, pos(x, y), stats.Earliness[y][x])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestOpponentModelConcurrentGames(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	model := NewOpponentModel(filepath.Join(t.TempDir(), "opponents.json"), 1, time.Minute, logger)
+
+	now := time.Now()
+	model.now = func() time.Time { return now }
+
+	for _, gameId := range []string{"first", "second", "third"} {
+		model.StartGame(gameId, "Opponent")
+	}
+
+	observe(model, "first", []core.BattleshipPos{{X: 0, Y: 0}}, []core.BattleshipPos{{X: 1, Y: 0}})
+	observe(model, "second", []core.BattleshipPos{{X: 0, Y: 1}})
+
+	// starting games doesn't end others against the same opponent
+	if stats, _ := model.Stats("Opponent"); stats.Games != 0 {
+		t.Fatalf("expected no recorded games, got %v", stats.Games)
+	}
+
+	model.EndGame("first", "Opponent", nil)
+
+	if stats, _ := model.Stats("Opponent"); stats.Games != 1 || stats.Earliness[0][1] != 0.99 {
+		t.Fatalf("expected the first game with both shots, got games %v, earliness %v", stats.Games, stats.Earliness[0][1])
+	}
+
+	// unary games without strikes for the timeout are recorded as over
+	now = now.Add(time.Minute / 2)
+	observe(model, "second", []core.BattleshipPos{{X: 0, Y: 1}, {X: 0, Y: 2}})
+	now = now.Add(time.Minute / 2)
+	model.StartGame("fourth", "Opponent")
+
+	if stats, _ := model.Stats("Opponent"); stats.Games != 2 || stats.Earliness[1][0] != 0 {
+		t.Fatalf("expected only the idle third game to be recorded, got games %v", stats.Games)
+	}
+
+	if name, _ := model.ObserveShots("second", core.NewBattleshipField()); name != "Opponent" {
+		t.Fatalf("expected the second game to be tracked, got %q", name)
+	}
+}
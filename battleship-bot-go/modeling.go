@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"math"
+
+	botsdk "github.com/mtratsiuk/battleship/battleship-go-bot-sdk"
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	strategy "github.com/mtratsiuk/battleship/battleship-go-strategy"
+)
+
+// modelingCandidates is the number of fleets the placer chooses from against
+// an opponent it's fully confident about.
+const modelingCandidates = 16
+
+// ModelingPlacer places fleets with the strategy, but against opponents
+// with known statistics it places several and keeps the one whose ships the
+// opponent tends to shoot the latest. The more games of the opponent were
+// seen, the more fleets it chooses from.
+type ModelingPlacer struct {
+	Strategy core.BattleshipPlacementStrategy
+	Model    *OpponentModel
+}
+
+func (p ModelingPlacer) Place(ctx context.Context, gameId string) (core.BattleshipField, error) {
+	rng := botsdk.NewRand()
+
+	opponent := botsdk.OpponentName(ctx)
+	if opponent == "" {
+		return p.Strategy.Place(ctx, rng)
+	}
+
+	p.Model.StartGame(gameId, opponent)
+
+	stats, _ := p.Model.Stats(opponent)
+	candidates := 1 + int(math.Round(stats.Confidence()*(modelingCandidates-1)))
+	earliness := stats.MeanEarliness()
+
+	var best core.BattleshipField
+	bestScore := math.Inf(1)
+
+	for i := 0; i < candidates; i += 1 {
+		f, err := p.Strategy.Place(ctx, rng)
+		if err != nil {
+			// any complete fleet is better than none once out of time
+			if i > 0 && ctx.Err() != nil {
+				break
+			}
+
+			return f, err
+		}
+
+		score := 0.0
+		for y := 0; y < core.BattleshipFieldSize; y += 1 {
+			for x := 0; x < core.BattleshipFieldSize; x += 1 {
+				if f.Field[y][x].Kind != core.BattleshipTileKindEmpty {
+					score += earliness[y][x]
+				}
+			}
+		}
+
+		if score < bestScore {
+			best, bestScore = f, score
+		}
+	}
+
+	return best, nil
+}
+
+// ModelingShooter shoots with the strategy, recording shots of the opponent
+// at the bot's field, in the order of strike outcomes in streamed games. Against opponents with known fleets a density
+// targeting strategy strikes where the opponent tends to place ships: the
+// density of every position is multiplied by the odds of it holding a ship.
+// Other strategies aren't biased.
+type ModelingShooter struct {
+	Strategy core.BattleshipTargetingStrategy
+	Model    *OpponentModel
+}
+
+func (s ModelingShooter) Shoot(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	_, stats := s.Model.ObserveShots(gameId, own)
+
+	density, ok := densityTargeting(s.Strategy)
+	if !ok || stats.Layouts == 0 {
+		return botsdk.StrategyShooter{Strategy: s.Strategy}.Shoot(ctx, gameId, own, other)
+	}
+
+	view := core.NewBattleshipOpponentViewFromField(other)
+	weights := density.Density(&view)
+	odds := stats.ShipOdds()
+
+	best := make([]core.BattleshipPos, 0)
+	bestWeight := -1.0
+
+	for _, pos := range view.Unknown() {
+		cur := weights[pos.Y][pos.X] * odds[pos.Y][pos.X]
+
+		if cur > bestWeight {
+			best = best[:0]
+			bestWeight = cur
+		}
+
+		if cur == bestWeight {
+			best = append(best, pos)
+		}
+	}
+
+	if len(best) == 0 {
+		return core.BattleshipPos{}, botsdk.NewNoPositionsLeftError()
+	}
+
+	return best[botsdk.NewRand().Intn(len(best))], nil
+}
+
+func (s ModelingShooter) StrikeOutcome(ctx context.Context, gameId string, pos core.BattleshipPos, own, hit bool) {
+	if !own {
+		s.Model.ObserveShot(gameId, pos)
+	}
+}
+
+func (s ModelingShooter) GameOver(ctx context.Context, gameId string, won bool, opponent *core.BattleshipField) {
+	s.Model.EndGame(gameId, botsdk.OpponentName(ctx), opponent)
+}
+
+func densityTargeting(t core.BattleshipTargetingStrategy) (strategy.DensityTargeting, bool) {
+	switch d := t.(type) {
+	case strategy.DensityTargeting:
+		return d, true
+	case *strategy.DensityTargeting:
+		return *d, true
+	default:
+		return strategy.DensityTargeting{}, false
+	}
+}
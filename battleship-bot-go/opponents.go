@@ -0,0 +1,470 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// opponentPriorGames is the number of games worth of uniform statistics
+// mixed into what is learned about an opponent, so a few games don't bias
+// the bot too much.
+const opponentPriorGames = 5
+
+type tileStats [core.BattleshipFieldSize][core.BattleshipFieldSize]float64
+
+// OpponentStats is what the bot learned about an opponent. Counts decay:
+// recording a game multiplies counts of previous ones by the model's decay,
+// so habits the opponent dropped fade out.
+type OpponentStats struct {
+	// Layouts is the number of the opponent's fleets seen, Ships counts the
+	// fleets having a ship at every position.
+	Layouts float64   `json:"layouts"`
+	Ships   tileStats `json:"ships"`
+	// Games is the number of games the opponent's shots were seen in,
+	// Earliness sums how early every position was shot in them, from 1 for
+	// the first shot of a game down to 0 for positions never shot.
+	Games     float64   `json:"games"`
+	Earliness tileStats `json:"earliness"`
+}
+
+func (s *OpponentStats) AddLayout(f core.BattleshipField, decay float64) {
+	s.Layouts = s.Layouts*decay + 1
+
+	for y := 0; y < core.BattleshipFieldSize; y += 1 {
+		for x := 0; x < core.BattleshipFieldSize; x += 1 {
+			s.Ships[y][x] *= decay
+
+			if f.Field[y][x].Kind != core.BattleshipTileKindEmpty {
+				s.Ships[y][x] += 1
+			}
+		}
+	}
+}
+
+// AddShots records positions the opponent shot in a game, in order.
+func (s *OpponentStats) AddShots(shots []core.BattleshipPos, decay float64) {
+	s.Games = s.Games*decay + 1
+
+	for y := 0; y < core.BattleshipFieldSize; y += 1 {
+		for x := 0; x < core.BattleshipFieldSize; x += 1 {
+			s.Earliness[y][x] *= decay
+		}
+	}
+
+	tiles := float64(core.BattleshipFieldSize * core.BattleshipFieldSize)
+
+	for i, pos := range shots {
+		if pos.IsInBounds() {
+			s.Earliness[pos.Y][pos.X] += max(1-float64(i)/tiles, 0)
+		}
+	}
+}
+
+// ShipOdds returns how much more likely than average every position is to
+// hold a ship of the opponent, 1 for all positions if no layouts were seen.
+func (s *OpponentStats) ShipOdds() tileStats {
+	average := float64(core.BattleshipTilesToHitCount) / float64(core.BattleshipFieldSize*core.BattleshipFieldSize)
+	odds := tileStats{}
+
+	for y := 0; y < core.BattleshipFieldSize; y += 1 {
+		for x := 0; x < core.BattleshipFieldSize; x += 1 {
+			p := (s.Ships[y][x] + opponentPriorGames*average) / (s.Layouts + opponentPriorGames)
+			odds[y][x] = p / average
+		}
+	}
+
+	return odds
+}
+
+// MeanEarliness returns how early the opponent shoots every position on
+// average, see Earliness.
+func (s *OpponentStats) MeanEarliness() tileStats {
+	mean := tileStats{}
+
+	if s.Games == 0 {
+		return mean
+	}
+
+	for y := 0; y < core.BattleshipFieldSize; y += 1 {
+		for x := 0; x < core.BattleshipFieldSize; x += 1 {
+			mean[y][x] = s.Earliness[y][x] / s.Games
+		}
+	}
+
+	return mean
+}
+
+// Confidence goes from 0 to 1 as more of the opponent's games are seen.
+func (s *OpponentStats) Confidence() float64 {
+	return s.Games / (s.Games + opponentPriorGames)
+}
+
+// modeledGame is a game in progress against a named opponent.
+type modeledGame struct {
+	opponent string
+	// shots of the opponent in order, shot is the set of them
+	shots []core.BattleshipPos
+	shot  map[core.BattleshipPos]bool
+	// seen is when the game was last played
+	seen time.Time
+}
+
+// OpponentModel keeps statistics of opponents by name, learned from their
+// fleets revealed once games are over and from the order of their shots.
+// Statistics are saved to a file after every recorded game.
+type OpponentModel struct {
+	path   string
+	decay  float64
+	logger *slog.Logger
+	// gameTimeout is the time after which idle games are recorded as over
+	gameTimeout time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	opponents map[string]*OpponentStats
+	// archived are ids of archive games already recorded
+	archived map[string]bool
+	games    map[string]*modeledGame
+}
+
+func NewOpponentModel(path string, decay float64, gameTimeout time.Duration, logger *slog.Logger) *OpponentModel {
+	m := &OpponentModel{}
+	m.path = path
+	m.decay = decay
+	m.logger = logger
+	m.gameTimeout = gameTimeout
+	m.now = time.Now
+	m.opponents = make(map[string]*OpponentStats)
+	m.archived = make(map[string]bool)
+	m.games = make(map[string]*modeledGame)
+
+	return m
+}
+
+type opponentModelFile struct {
+	Opponents     map[string]*OpponentStats `json:"opponents"`
+	ArchivedGames []string                  `json:"archived_games"`
+}
+
+// Load reads statistics saved to the model's file, if it exists.
+func (m *OpponentModel) Load() error {
+	b, err := os.ReadFile(m.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	f := opponentModelFile{}
+	if err := json.Unmarshal(b, &f); err != nil {
+		return fmt.Errorf("failed to parse %v: %w", m.path, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, stats := range f.Opponents {
+		m.opponents[name] = stats
+	}
+
+	for _, id := range f.ArchivedGames {
+		m.archived[id] = true
+	}
+
+	return nil
+}
+
+// save writes statistics to the model's file, replacing it only once it's
+// fully written.
+func (m *OpponentModel) save() error {
+	f := opponentModelFile{}
+	f.Opponents = m.opponents
+	f.ArchivedGames = make([]string, 0, len(m.archived))
+
+	for id := range m.archived {
+		f.ArchivedGames = append(f.ArchivedGames, id)
+	}
+	slices.Sort(f.ArchivedGames)
+
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, m.path)
+}
+
+// Stats returns a copy of the statistics of the opponent.
+func (m *OpponentModel) Stats(opponent string) (OpponentStats, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.opponents[opponent]
+	if !ok {
+		return OpponentStats{}, false
+	}
+
+	return *stats, true
+}
+
+// StartGame starts tracking shots of the opponent in the game, until
+// EndGame is called. Bots playing over the unary API aren't told when games
+// are over, so games without strikes for the model's game timeout are
+// recorded as they are.
+func (m *OpponentModel) StartGame(gameId, opponent string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expireGames()
+
+	// requests may be retried
+	if g, ok := m.games[gameId]; ok {
+		g.seen = m.now()
+		return
+	}
+
+	g := &modeledGame{}
+	g.opponent = opponent
+	g.shots = make([]core.BattleshipPos, 0)
+	g.shot = make(map[core.BattleshipPos]bool)
+	g.seen = m.now()
+	m.games[gameId] = g
+}
+
+// ObserveShots records shots of the opponent at the bot's own field made
+// since the last call, returning the opponent's name and statistics. The
+// name is empty for games not started with StartGame.
+//
+// Hits and misses are unordered, so positions new since the last call are
+// recorded in row-major order. There is a single one unless requests were
+// missed, or the opponent strikes first.
+func (m *OpponentModel) ObserveShots(gameId string, own core.BattleshipField) (string, OpponentStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expireGames()
+
+	g, ok := m.games[gameId]
+	if !ok {
+		return "", OpponentStats{}
+	}
+
+	// items are the sets' own slices, so they're copied before sorting
+	shots := make([]core.BattleshipPos, 0, len(own.Hits.Items())+len(own.Misses.Items()))
+	shots = append(shots, own.Hits.Items()...)
+	shots = append(shots, own.Misses.Items()...)
+	slices.SortFunc(shots, func(a, b core.BattleshipPos) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+
+		return a.X - b.X
+	})
+
+	for _, pos := range shots {
+		g.add(pos)
+	}
+	g.seen = m.now()
+
+	stats, ok := m.opponents[g.opponent]
+	if !ok {
+		return g.opponent, OpponentStats{}
+	}
+
+	return g.opponent, *stats
+}
+
+// ObserveShot records a shot of the opponent, in the order shots were made,
+// e.g. from strike outcomes of a streamed game.
+func (m *OpponentModel) ObserveShot(gameId string, pos core.BattleshipPos) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.games[gameId]; ok {
+		g.add(pos)
+		g.seen = m.now()
+	}
+}
+
+func (g *modeledGame) add(pos core.BattleshipPos) {
+	if !g.shot[pos] {
+		g.shot[pos] = true
+		g.shots = append(g.shots, pos)
+	}
+}
+
+// expireGames records games idle for longer than the game timeout.
+func (m *OpponentModel) expireGames() {
+	recorded := false
+
+	for id, g := range m.games {
+		if m.now().Sub(g.seen) < m.gameTimeout {
+			continue
+		}
+
+		m.stats(g.opponent).AddShots(g.shots, m.decay)
+		delete(m.games, id)
+		recorded = true
+
+		m.logger.Info(fmt.Sprintf("Recorded game %v against %v, idle for %v", id, g.opponent, m.gameTimeout))
+	}
+
+	if recorded {
+		m.saveOrWarn()
+	}
+}
+
+// EndGame records the game with its shots and the opponent's fleet, if it
+// was revealed.
+func (m *OpponentModel) EndGame(gameId, opponent string, fleet *core.BattleshipField) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if g, ok := m.games[gameId]; ok {
+		opponent = g.opponent
+		m.stats(opponent).AddShots(g.shots, m.decay)
+		delete(m.games, gameId)
+	}
+
+	if opponent == "" {
+		return
+	}
+
+	if fleet != nil {
+		m.stats(opponent).AddLayout(*fleet, m.decay)
+	}
+
+	m.logger.Info(fmt.Sprintf("Recorded game %v against %v", gameId, opponent))
+	m.saveOrWarn()
+}
+
+// ImportArchive records games of a directory of GameProto JSON files, e.g.
+// written by battleship-tournament --logs-dir, skipping ones already
+// recorded. Fleets and shots of both players are recorded.
+func (m *OpponentModel) ImportArchive(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	imported := 0
+	errs := make([]error, 0)
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		game := pbserver.GameProto{}
+		if err := protojson.Unmarshal(b, &game); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %v: %w", e.Name(), err))
+			continue
+		}
+
+		if game.Id == "" || m.archived[game.Id] {
+			continue
+		}
+
+		if err := m.recordGame(&game); err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", e.Name(), err))
+			continue
+		}
+
+		m.archived[game.Id] = true
+		imported += 1
+	}
+
+	if imported > 0 {
+		if err := m.save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return imported, errors.Join(errs...)
+}
+
+// recordGame records fleets and shots of both players of an archived game.
+func (m *OpponentModel) recordGame(game *pbserver.GameProto) error {
+	names := map[string]string{
+		game.GetPlayer_1().GetId(): game.GetPlayer_1().GetName(),
+		game.GetPlayer_2().GetId(): game.GetPlayer_2().GetName(),
+	}
+
+	fleets := make(map[string]core.BattleshipField)
+	shots := make(map[string][]core.BattleshipPos)
+
+	for _, entry := range game.Log {
+		switch {
+		case entry.GetField() != nil:
+			f, err := core.NewBattleshipFieldFromProto(entry.GetField().GetField())
+			if err != nil {
+				return err
+			}
+
+			fleets[entry.GetField().PlayerId] = f
+		case entry.GetStrike() != nil && entry.GetStrike().Position != nil:
+			id := entry.GetStrike().AttackerId
+			shots[id] = append(shots[id], core.NewBattleshipPosFromProto(entry.GetStrike().Position))
+		}
+	}
+
+	for id, name := range names {
+		if name == "" {
+			continue
+		}
+
+		if f, ok := fleets[id]; ok {
+			m.stats(name).AddLayout(f, m.decay)
+		}
+
+		if s, ok := shots[id]; ok {
+			m.stats(name).AddShots(s, m.decay)
+		}
+	}
+
+	return nil
+}
+
+func (m *OpponentModel) stats(opponent string) *OpponentStats {
+	stats, ok := m.opponents[opponent]
+	if !ok {
+		stats = &OpponentStats{}
+		m.opponents[opponent] = stats
+	}
+
+	return stats
+}
+
+func (m *OpponentModel) saveOrWarn() {
+	if err := m.save(); err != nil {
+		m.logger.Warn(fmt.Sprintf("Failed to save opponent statistics: %v", err))
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"slices"
@@ -12,6 +13,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/jroimartin/gocui"
 	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
 	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
 )
 
@@ -20,6 +22,7 @@ const (
 
 	VIEW_GAMES_LIST = "games-list-view"
 	VIEW_PLAYERS    = "players-view"
+	VIEW_BOT_INFO   = "bot-info-view"
 	VIEW_GAME       = "game-view"
 	VIEW_ERRORS     = "errors-view"
 	VIEW_FIELD_1    = "field-1-view"
@@ -27,7 +30,32 @@ const (
 	VIEW_HOT_KEYS   = "hot-keys-view"
 )
 
+type Config struct {
+	core.ServerClientConfig `yaml:",inline"`
+
+	RequestTimeout time.Duration `yaml:"request_timeout" toml:"request_timeout" env:"BATTLESHIP_CLI_REQUEST_TIMEOUT" flag:"request-timeout" usage:"timeout of battleship server requests"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.ServerClientConfig = core.NewServerClientConfig()
+	c.RequestTimeout = 10 * time.Second
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := []error{c.ServerClientConfig.Validate()}
+
+	if c.RequestTimeout <= 0 {
+		errs = append(errs, errors.New("request_timeout must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
 type App struct {
+	config Config
 	client pbserver.BattleshipServerServiceClient
 	close  func()
 
@@ -44,6 +72,7 @@ type AppPlayer struct {
 	id   string
 	name string
 	wins int
+	info *pbcore.BotInfoProto
 }
 
 type AppGameRenderer struct {
@@ -53,9 +82,10 @@ type AppGameRenderer struct {
 	cancel      func()
 }
 
-func NewApp() App {
+func NewApp(config Config) App {
 
 	app := App{}
+	app.config = config
 	app.games = make([]*pbserver.GetGamesResponseEntry, 0)
 	app.players = make([]AppPlayer, 0)
 	app.err = ""
@@ -63,20 +93,23 @@ func NewApp() App {
 	app.curGameIdx = 0
 	app.curPlayerIdx = 0
 
-	client, close, err := core.NewBattleshipServerServiceClient()
+	client, close, err := config.ServerClientConfig.NewClient()
 
 	if err != nil {
 		log.Panicln(err)
 	}
 
 	app.close = func() { close() }
-	app.client = *client
+	app.client = client
 
 	return app
 }
 
 func main() {
-	app := NewApp()
+	config := NewConfig()
+	core.MustLoadConfig(&config, "BATTLESHIP_CLI_CONFIG")
+
+	app := NewApp(config)
 	defer app.close()
 
 	g, err := gocui.NewGui(gocui.OutputNormal)
@@ -137,6 +170,10 @@ func (app *App) Layout(g *gocui.Gui) error {
 		return err
 	}
 
+	if err := app.BotInfoView(g); err != nil {
+		return err
+	}
+
 	if err := app.GameView(g); err != nil {
 		return err
 	}
@@ -176,7 +213,7 @@ func (app *App) GamesListView(g *gocui.Gui) error {
 func (app *App) PlayersView(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
 
-	if v, err := g.SetView(VIEW_PLAYERS, maxX/2+PADDING, PADDING, maxX-PADDING, maxY/2-PADDING); err != nil {
+	if v, err := g.SetView(VIEW_PLAYERS, maxX/2+PADDING, PADDING, maxX-PADDING, maxY/4-PADDING); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -186,6 +223,19 @@ func (app *App) PlayersView(g *gocui.Gui) error {
 	return nil
 }
 
+func (app *App) BotInfoView(g *gocui.Gui) error {
+	maxX, maxY := g.Size()
+
+	if v, err := g.SetView(VIEW_BOT_INFO, maxX/2+PADDING, maxY/4, maxX-PADDING, maxY/2-PADDING); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+
+		app.RenderBotInfo(v)
+	}
+	return nil
+}
+
 func (app *App) GameView(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
 
@@ -297,6 +347,32 @@ func (app *App) RenderPlayers(v *gocui.View) error {
 	return nil
 }
 
+func (app *App) RenderBotInfo(v *gocui.View) error {
+	v.Clear()
+	v.Title = "Bot details"
+
+	if len(app.players) == 0 {
+		return nil
+	}
+
+	p := app.players[min(app.curPlayerIdx, len(app.players)-1)]
+
+	fmt.Fprintf(v, " %-10v %v\n", "Name:", p.name)
+
+	if p.info == nil {
+		fmt.Fprintf(v, " Bot doesn't provide details\n")
+		return nil
+	}
+
+	fmt.Fprintf(v, " %-10v %v\n", "Author:", p.info.Author)
+	fmt.Fprintf(v, " %-10v %v\n", "Version:", p.info.Version)
+	fmt.Fprintf(v, " %-10v %v\n", "Rulesets:", strings.Join(p.info.Rulesets, ", "))
+	fmt.Fprintf(v, " %-10v %v\n", "Variants:", strings.Join(p.info.Variants, ", "))
+	fmt.Fprintf(v, " %-10v %v\n", "Protocol:", p.info.ProtocolVersion)
+
+	return nil
+}
+
 func (app *App) RenderGame(v *gocui.View) error {
 	v.Clear()
 
@@ -398,6 +474,12 @@ func (app *App) ReRender(g *gocui.Gui) error {
 	}
 	app.RenderPlayers(v)
 
+	v, err = g.View(VIEW_BOT_INFO)
+	if err != nil {
+		return err
+	}
+	app.RenderBotInfo(v)
+
 	v, err = g.View(VIEW_ERRORS)
 	if err != nil {
 		return err
@@ -418,7 +500,7 @@ func (app *App) Quit(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (app *App) RefreshGames(g *gocui.Gui, _ *gocui.View) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.RequestTimeout)
 	defer cancel()
 
 	games, err := app.client.GetGames(ctx, &pbserver.GetGamesRequest{})
@@ -433,7 +515,7 @@ func (app *App) RefreshGames(g *gocui.Gui, _ *gocui.View) error {
 }
 
 func (app *App) AddRandomBot(g *gocui.Gui, v *gocui.View) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.RequestTimeout)
 	defer cancel()
 
 	_, err := app.client.AddRandomBot(ctx, &pbserver.AddRandomBotRequest{})
@@ -459,7 +541,7 @@ func (app *App) FetchGame(g *gocui.Gui, v *gocui.View) error {
 		return app.ReRender(g)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), app.config.RequestTimeout)
 	defer cancel()
 
 	response, err := app.client.GetGame(ctx, &pbserver.GetGameRequest{Id: game.Id})
@@ -526,8 +608,8 @@ func (app *App) SetGames(gamesResponse *pbserver.GetGamesResponse) {
 	players := make(map[string]*AppPlayer, 0)
 
 	for _, game := range app.games {
-		players[game.Player_1.Id] = &AppPlayer{game.Player_1.Id, game.Player_1.Name, 0}
-		players[game.Player_2.Id] = &AppPlayer{game.Player_2.Id, game.Player_2.Name, 0}
+		players[game.Player_1.Id] = &AppPlayer{game.Player_1.Id, game.Player_1.Name, 0, game.Player_1.Info}
+		players[game.Player_2.Id] = &AppPlayer{game.Player_2.Id, game.Player_2.Name, 0, game.Player_2.Info}
 	}
 
 	for _, game := range app.games {
@@ -117,6 +117,14 @@ class BattleshipGame(
     fun playerField(playerId: BattleshipPlayerId) =
         if (playerId == player1Id) player1Field else player2Field
 
+    /** Returns null if the player hasn't provided a field yet. */
+    fun playerFieldOrNull(playerId: BattleshipPlayerId) =
+        if (playerId == player1Id) {
+            if (::player1Field.isInitialized) player1Field else null
+        } else {
+            if (::player2Field.isInitialized) player2Field else null
+        }
+
     private inline fun <reified T : BattleshipState> assertState(assertPlayerId: (T) -> Unit) {
         require(state is T) { "Expected current game state to be ${typeOf<T>()}, got $state" }
         assertPlayerId(state as T)
@@ -0,0 +1,177 @@
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	AnytimeOutcomeCompleted = "completed"
+	AnytimeOutcomeFailed    = "failed"
+	AnytimeOutcomeBest      = "best"
+	AnytimeOutcomeFallback  = "fallback"
+
+	DegradationQueueFull    = "queue_full"
+	DegradationQueueTimeout = "queue_timeout"
+)
+
+// AnytimeStrategy computes an answer within ctx, publishing every improved
+// answer as soon as it is known. Strategy must return once ctx is done.
+type AnytimeStrategy[T any] func(ctx context.Context, publish func(T)) error
+
+// Executor runs anytime strategies within a time budget derived from the
+// request deadline, limiting the number of concurrent computations.
+type Executor struct {
+	safetyMargin  time.Duration
+	defaultBudget time.Duration
+	limiter       *Limiter
+	logger        *slog.Logger
+	metrics       *Metrics
+}
+
+func NewExecutor(safetyMargin, defaultBudget time.Duration, limiter *Limiter, logger *slog.Logger, metrics *Metrics) *Executor {
+	e := &Executor{}
+	e.safetyMargin = safetyMargin
+	e.defaultBudget = defaultBudget
+	e.limiter = limiter
+	e.logger = logger
+	e.metrics = metrics
+
+	return e
+}
+
+// Budget returns the time available to the strategy: the remaining time
+// until the ctx deadline minus the safety margin, or the default budget when
+// ctx has no deadline.
+func (e *Executor) Budget(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return e.defaultBudget
+	}
+
+	return time.Until(deadline) - e.safetyMargin
+}
+
+// RunAnytime runs strategy within the budget and returns its final answer.
+// If the budget runs out, the best published answer is returned, or the
+// fallback answer if nothing was published yet. Fallback answer is also used
+// right away when the strategy can't get a computation slot, either because
+// the queue is too deep or the budget ran out while waiting.
+func RunAnytime[T any](ctx context.Context, e *Executor, method string, strategy AnytimeStrategy[T], fallback func() (T, error)) (T, error) {
+	budget := e.Budget(ctx)
+	start := time.Now()
+
+	var (
+		mu        sync.Mutex
+		best      T
+		published bool
+	)
+
+	publish := func(v T) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		best = v
+		published = true
+	}
+
+	result := func() (T, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return best, published
+	}
+
+	report := func(outcome string) {
+		used := time.Since(start)
+		usage := 1.0
+		if budget > 0 {
+			usage = float64(used) / float64(budget)
+		}
+
+		level := slog.LevelInfo
+		if outcome == AnytimeOutcomeBest || outcome == AnytimeOutcomeFallback {
+			level = slog.LevelWarn
+		}
+
+		e.metrics.ObserveStrategy(method, start)
+		e.metrics.ObserveAnytime(method, outcome)
+		e.logger.LogAttrs(
+			ctx,
+			level,
+			fmt.Sprintf("Strategy finished with %v answer", outcome),
+			slog.Duration("Budget", budget),
+			slog.Duration("BudgetUsed", used),
+			slog.Float64("BudgetUsage", usage),
+		)
+	}
+
+	if budget <= 0 {
+		report(AnytimeOutcomeFallback)
+		return fallback()
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	// Game id is put into the context by LoggingInterceptor.
+	gameId, _ := ctx.Value(CtxKeyGameId).(string)
+
+	release, err := e.limiter.Acquire(runCtx, gameId)
+	if err != nil {
+		reason := DegradationQueueTimeout
+		if errors.Is(err, ErrQueueFull) {
+			reason = DegradationQueueFull
+		}
+
+		e.metrics.ObserveDegradation(method, reason)
+		e.logger.WarnContext(ctx, fmt.Sprintf("Strategy degraded to fallback: %v", reason))
+
+		report(AnytimeOutcomeFallback)
+		return fallback()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		// Slot is released once the strategy actually stops, even if its
+		// answer was not awaited.
+		defer release()
+		done <- strategy(runCtx, publish)
+	}()
+
+	select {
+	case err := <-done:
+		if v, ok := result(); ok {
+			report(AnytimeOutcomeCompleted)
+			return v, nil
+		}
+
+		// strategies return as soon as the budget runs out, which may win
+		// the race with runCtx.Done()
+		if runCtx.Err() != nil {
+			report(AnytimeOutcomeFallback)
+			return fallback()
+		}
+
+		if err != nil {
+			report(AnytimeOutcomeFailed)
+			var zero T
+			return zero, err
+		}
+
+		report(AnytimeOutcomeFallback)
+		return fallback()
+	case <-runCtx.Done():
+		if v, ok := result(); ok {
+			report(AnytimeOutcomeBest)
+			return v, nil
+		}
+
+		report(AnytimeOutcomeFallback)
+		return fallback()
+	}
+}
@@ -0,0 +1,38 @@
+package botsdk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRunAnytimeFallsBackWhenBudgetRunsOut(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	e := NewExecutor(0, time.Microsecond, NewLimiter(100, 100), logger, NewMetrics(prometheus.NewRegistry(), "test"))
+
+	blocking := func(ctx context.Context, publish func(int)) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	fallback := func() (int, error) {
+		return 42, nil
+	}
+
+	// the strategy returns right as the budget runs out, so either select
+	// branch may be taken
+	for i := 0; i < 100; i += 1 {
+		v, err := RunAnytime(context.Background(), e, "GetField", blocking, fallback)
+		if err != nil {
+			t.Fatalf("run %v: expected fallback answer, got error %v", i, err)
+		}
+
+		if v != 42 {
+			t.Fatalf("run %v: expected fallback answer 42, got %v", i, v)
+		}
+	}
+}
@@ -0,0 +1,710 @@
+// Package botsdk implements everything a battleship bot needs besides its
+// strategy: gRPC serving, lobby registration, configuration, logging,
+// metrics, request validation, time budgets and graceful shutdown.
+//
+// A bot is a Placer and a Shooter:
+//
+//	func main() {
+//		botsdk.Main(MyPlacer{}, MyShooter{})
+//	}
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Placer places the bot's fleet at the start of a game.
+type Placer interface {
+	Place(ctx context.Context, gameId string) (core.BattleshipField, error)
+}
+
+// Shooter chooses the next position to strike. Own field contains the bot's
+// fleet with opponent's hits and misses, other field contains only the bot's
+// hits and misses.
+type Shooter interface {
+	Shoot(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error)
+}
+
+// AnytimeShooter can be implemented by shooters that improve their answer
+// over time: every published position may be used if the time budget runs out.
+type AnytimeShooter interface {
+	ShootAnytime(ctx context.Context, gameId string, own, other core.BattleshipField, publish func(core.BattleshipPos)) error
+}
+
+// GameOverObserver can be implemented by placers and shooters that learn
+// from finished games. It's called for games played over PlayGame, on the
+// placer and on the shooter. Opponent is the opponent's fleet revealed by
+// the server, or nil if it wasn't.
+type GameOverObserver interface {
+	GameOver(ctx context.Context, gameId string, won bool, opponent *core.BattleshipField)
+}
+
+// StrikeOutcomeObserver can be implemented by placers and shooters that
+// follow games played over PlayGame strike by strike. It's called with the
+// outcome of every strike made by either player, in the order they were
+// made. Own is true for strikes made by the bot.
+type StrikeOutcomeObserver interface {
+	StrikeOutcome(ctx context.Context, gameId string, pos core.BattleshipPos, own, hit bool)
+}
+
+// OpponentName returns the name of the opponent of the game being played,
+// or an empty string if the server doesn't tell it. Only GetField requests
+// and PlayGame streams carry the name.
+func OpponentName(ctx context.Context) string {
+	name, _ := ctx.Value(CtxKeyOpponentName).(string)
+	return name
+}
+
+// ConfigEnv names the environment variable pointing to the bot config file.
+const ConfigEnv = "BATTLESHIP_BOT_GO_CONFIG"
+
+const MinSecretLength = 16
+
+// Config is loaded with core.LoadConfig, see NewConfig for defaults.
+type Config struct {
+	core.ServerClientConfig `yaml:",inline"`
+
+	GrpcHost         string        `yaml:"grpc_host" toml:"grpc_host" env:"BATTLESHIP_BOT_GO_GRPC_HOST" flag:"grpc-host" usage:"host to serve the bot gRPC API at"`
+	GrpcPort         string        `yaml:"grpc_port" toml:"grpc_port" env:"BATTLESHIP_BOT_GO_GRPC_PORT" flag:"grpc-port" usage:"port to serve the bot gRPC API at"`
+	ExternalAddr     string        `yaml:"external_addr" toml:"external_addr" env:"BATTLESHIP_BOT_GO_EXTERNAL_ADDR" flag:"external-addr" usage:"address the server reaches the bot at"`
+	Reverse          bool          `yaml:"reverse" toml:"reverse" env:"BATTLESHIP_BOT_GO_REVERSE" flag:"reverse" usage:"connect to the server and take requests over the connection instead of serving the bot gRPC API, for bots the server can't reach"`
+	ReconnectDelay   time.Duration `yaml:"reconnect_delay" toml:"reconnect_delay" env:"BATTLESHIP_BOT_GO_RECONNECT_DELAY" flag:"reconnect-delay" usage:"delay before reconnecting a lost reverse connection"`
+	Name             string        `yaml:"name" toml:"name" env:"BATTLESHIP_BOT_GO_NAME" flag:"name" usage:"bot name shown in the lobby"`
+	Author           string        `yaml:"author" toml:"author" env:"BATTLESHIP_BOT_GO_AUTHOR" flag:"author" usage:"bot author reported by GetInfo"`
+	Version          string        `yaml:"version" toml:"version" env:"BATTLESHIP_BOT_GO_VERSION" flag:"bot-version" usage:"bot version reported by GetInfo"`
+	Rulesets         []string      `yaml:"rulesets" toml:"rulesets" env:"BATTLESHIP_BOT_GO_RULESETS" flag:"rulesets" usage:"rulesets the bot can play"`
+	Variants         []string      `yaml:"variants" toml:"variants" env:"BATTLESHIP_BOT_GO_VARIANTS" flag:"variants" usage:"ruleset variants the bot can play"`
+	MetricsHost      string        `yaml:"metrics_host" toml:"metrics_host" env:"BATTLESHIP_BOT_GO_METRICS_HOST" flag:"metrics-host" usage:"host to serve metrics at"`
+	MetricsPort      string        `yaml:"metrics_port" toml:"metrics_port" env:"BATTLESHIP_BOT_GO_METRICS_PORT" flag:"metrics-port" usage:"port to serve metrics at"`
+	LogLevel         string        `yaml:"log_level" toml:"log_level" env:"BATTLESHIP_BOT_GO_LOG_LEVEL" flag:"log-level" usage:"log level: debug, info, warn or error"`
+	LogFormat        string        `yaml:"log_format" toml:"log_format" env:"BATTLESHIP_BOT_GO_LOG_FORMAT" flag:"log-format" usage:"log format: json or text"`
+	JoinDelay        time.Duration `yaml:"join_delay" toml:"join_delay" env:"BATTLESHIP_BOT_GO_JOIN_DELAY" flag:"join-delay" usage:"delay before joining the lobby"`
+	SafetyMargin     time.Duration `yaml:"safety_margin" toml:"safety_margin" env:"BATTLESHIP_BOT_GO_SAFETY_MARGIN" flag:"safety-margin" usage:"part of the request deadline reserved for responding"`
+	DefaultBudget    time.Duration `yaml:"default_budget" toml:"default_budget" env:"BATTLESHIP_BOT_GO_DEFAULT_BUDGET" flag:"default-budget" usage:"strategy time budget of requests without a deadline"`
+	MaxConcurrency   int           `yaml:"max_concurrency" toml:"max_concurrency" env:"BATTLESHIP_BOT_GO_MAX_CONCURRENCY" flag:"max-concurrency" usage:"maximum number of concurrent strategy computations"`
+	MaxQueueDepth    int           `yaml:"max_queue_depth" toml:"max_queue_depth" env:"BATTLESHIP_BOT_GO_MAX_QUEUE_DEPTH" flag:"max-queue-depth" usage:"maximum number of strategy computations waiting to run"`
+	TLSCertFile      string        `yaml:"tls_cert_file" toml:"tls_cert_file" env:"BATTLESHIP_BOT_GO_TLS_CERT_FILE" flag:"tls-cert-file" usage:"certificate to serve the bot gRPC API over TLS with, reloaded on change"`
+	TLSKeyFile       string        `yaml:"tls_key_file" toml:"tls_key_file" env:"BATTLESHIP_BOT_GO_TLS_KEY_FILE" flag:"tls-key-file" usage:"TLS certificate key"`
+	TLSClientCAFile  string        `yaml:"tls_client_ca_file" toml:"tls_client_ca_file" env:"BATTLESHIP_BOT_GO_TLS_CLIENT_CA_FILE" flag:"tls-client-ca-file" usage:"CA certificates client certificates must be signed by, enables mutual TLS"`
+	TLSClientName    string        `yaml:"tls_client_name" toml:"tls_client_name" env:"BATTLESHIP_BOT_GO_TLS_CLIENT_NAME" flag:"tls-client-name" usage:"name client certificates must be issued to, e.g. battleship-server"`
+	Secret           string        `yaml:"secret" toml:"secret" env:"BATTLESHIP_BOT_GO_SECRET" flag:"secret" usage:"secret reserving the bot name in the lobby, requests not signed with it are rejected" secret:"true"`
+	SignatureMaxSkew time.Duration `yaml:"signature_max_skew" toml:"signature_max_skew" env:"BATTLESHIP_BOT_GO_SIGNATURE_MAX_SKEW" flag:"signature-max-skew" usage:"maximum age of signed requests"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.ServerClientConfig = core.NewServerClientConfig()
+	c.GrpcHost = "0.0.0.0"
+	c.GrpcPort = "6968"
+	c.ExternalAddr = "0.0.0.0:6968"
+	c.Reverse = false
+	c.ReconnectDelay = 5 * time.Second
+	c.Name = "Go Bot"
+	c.Author = ""
+	c.Version = "dev"
+	c.Rulesets = []string{core.BattleshipDefaultRuleset}
+	c.Variants = []string{core.BattleshipDefaultVariant}
+	c.MetricsHost = "0.0.0.0"
+	c.MetricsPort = "6967"
+	c.LogLevel = "info"
+	c.LogFormat = "json"
+	c.JoinDelay = 5 * time.Second
+	c.SafetyMargin = 50 * time.Millisecond
+	c.DefaultBudget = time.Second
+	c.MaxConcurrency = runtime.NumCPU()
+	c.MaxQueueDepth = 64
+	c.SignatureMaxSkew = time.Minute
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if err := c.ServerClientConfig.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := core.ValidatePort(c.GrpcPort); err != nil {
+		errs = append(errs, fmt.Errorf("grpc_port: %w", err))
+	}
+
+	if err := core.ValidatePort(c.MetricsPort); err != nil {
+		errs = append(errs, fmt.Errorf("metrics_port: %w", err))
+	}
+
+	if c.ExternalAddr == "" {
+		errs = append(errs, errors.New("external_addr must not be empty"))
+	}
+
+	if c.ReconnectDelay <= 0 {
+		errs = append(errs, errors.New("reconnect_delay must be positive"))
+	}
+
+	if c.Name == "" {
+		errs = append(errs, errors.New("name must not be empty"))
+	}
+
+	if len(c.Rulesets) == 0 {
+		errs = append(errs, errors.New("rulesets must not be empty"))
+	}
+
+	if len(c.Variants) == 0 {
+		errs = append(errs, errors.New("variants must not be empty"))
+	}
+
+	if _, err := NewLogger(io.Discard, c.LogLevel, "json"); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %w", err))
+	}
+
+	if _, err := NewLogger(io.Discard, "info", c.LogFormat); err != nil {
+		errs = append(errs, fmt.Errorf("log_format: %w", err))
+	}
+
+	if c.JoinDelay < 0 {
+		errs = append(errs, errors.New("join_delay must not be negative"))
+	}
+
+	if c.SafetyMargin < 0 {
+		errs = append(errs, errors.New("safety_margin must not be negative"))
+	}
+
+	if c.DefaultBudget <= 0 {
+		errs = append(errs, errors.New("default_budget must be positive"))
+	}
+
+	if c.MaxConcurrency < 1 {
+		errs = append(errs, errors.New("max_concurrency must be positive"))
+	}
+
+	if c.MaxQueueDepth < 0 {
+		errs = append(errs, errors.New("max_queue_depth must not be negative"))
+	}
+
+	if c.Secret != "" && len(c.Secret) < MinSecretLength {
+		errs = append(errs, fmt.Errorf("secret must be at least %v characters long", MinSecretLength))
+	}
+
+	if c.SignatureMaxSkew <= 0 {
+		errs = append(errs, errors.New("signature_max_skew must be positive"))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("tls_cert_file and tls_key_file must be set together"))
+	}
+
+	if c.TLSCertFile == "" && c.TLSClientCAFile != "" {
+		errs = append(errs, errors.New("tls_client_ca_file requires tls_cert_file"))
+	}
+
+	if c.TLSClientCAFile == "" && c.TLSClientName != "" {
+		errs = append(errs, errors.New("tls_client_name requires tls_client_ca_file"))
+	}
+
+	if c.TLSCertFile != "" && c.TLSKeyFile != "" {
+		if _, err := NewCertReloader(c.TLSCertFile, c.TLSKeyFile, c.TLSClientCAFile, slog.Default()); err != nil {
+			errs = append(errs, fmt.Errorf("tls: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Info returns the bot info reported by GetInfo.
+func (c Config) Info() *pbcore.BotInfoProto {
+	info := &pbcore.BotInfoProto{}
+	info.Name = c.Name
+	info.Author = c.Author
+	info.Version = c.Version
+	info.Rulesets = c.Rulesets
+	info.Variants = c.Variants
+	info.ProtocolVersion = core.BattleshipProtocolVersion
+	info.Capabilities = []string{core.BattleshipCapabilityPlayGameStream}
+
+	return info
+}
+
+type Bot struct {
+	config   Config
+	logger   *slog.Logger
+	metrics  *Metrics
+	executor *Executor
+	server   *BotServer
+}
+
+// NewBot creates a standalone bot with its own logger, metrics and limiter.
+// Metrics of a standalone bot are not served, use Group to serve them.
+func NewBot(config Config, placer Placer, shooter Shooter) (*Bot, error) {
+	logger, err := NewLogger(os.Stdout, config.LogLevel, config.LogFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	limiter := NewLimiter(config.MaxConcurrency, config.MaxQueueDepth)
+
+	return newBot(config, placer, shooter, logger, NewMetricsRegistry(), limiter), nil
+}
+
+func newBot(config Config, placer Placer, shooter Shooter, logger *slog.Logger, registerer prometheus.Registerer, limiter *Limiter) *Bot {
+	b := &Bot{}
+	b.config = config
+	b.logger = logger.With(slog.String("Bot", config.Name))
+	b.metrics = NewMetrics(registerer, config.Name)
+	b.executor = NewExecutor(config.SafetyMargin, config.DefaultBudget, limiter, b.logger, b.metrics)
+	b.server = NewBotServer(config.Info(), placer, shooter, b.executor, b.logger)
+
+	return b
+}
+
+func (b *Bot) Logger() *slog.Logger {
+	return b.logger
+}
+
+// interceptors returns interceptors of every request, besides validation.
+func (b *Bot) interceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		RequestIdInterceptor(),
+		LoggingInterceptor(b.logger),
+		b.metrics.UnaryServerInterceptor(),
+		RecoveryInterceptor(b.logger),
+	}
+}
+
+// NewGrpcServer creates a gRPC server with the bot service and interceptors registered.
+func (b *Bot) NewGrpcServer(opts ...grpc.ServerOption) *grpc.Server {
+	interceptors := b.interceptors()
+
+	if b.config.Secret != "" {
+		interceptors = append(interceptors, AuthInterceptor(b.config.Secret, b.config.SignatureMaxSkew))
+		opts = append(opts, grpc.StatsHandler(RequestBodyHandler{}))
+	}
+
+	streamInterceptors := make([]grpc.StreamServerInterceptor, 0, len(interceptors))
+	for _, i := range interceptors {
+		streamInterceptors = append(streamInterceptors, StreamInterceptor(i))
+	}
+
+	interceptors = append(interceptors, ValidationInterceptor())
+
+	opts = append(opts, grpc.ChainUnaryInterceptor(interceptors...), grpc.ChainStreamInterceptor(streamInterceptors...))
+
+	grpcServer := grpc.NewServer(opts...)
+
+	pbbot.RegisterBattleshipBotServiceServer(grpcServer, b.server)
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
+
+// Run serves the bot and joins the server lobby, or in reverse mode
+// connects to the server, until ctx is done.
+func (b *Bot) Run(ctx context.Context) error {
+	if b.config.Reverse {
+		return b.RunReverse(ctx)
+	}
+
+	grpcUrl := fmt.Sprintf("%v:%v", b.config.GrpcHost, b.config.GrpcPort)
+	lis, err := net.Listen("tcp", grpcUrl)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	opts := make([]grpc.ServerOption, 0)
+
+	if b.config.TLSCertFile != "" {
+		reloader, err := NewCertReloader(b.config.TLSCertFile, b.config.TLSKeyFile, b.config.TLSClientCAFile, b.logger)
+		if err != nil {
+			lis.Close()
+			return err
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.TLSConfig(b.config.TLSClientName))))
+	}
+
+	grpcServer := b.NewGrpcServer(opts...)
+
+	served := make(chan error, 1)
+	go func() {
+		b.logger.Info(fmt.Sprintf("Starting gRPC server at: %v, TLS: %v, mutual TLS: %v", grpcUrl, b.config.TLSCertFile != "", b.config.TLSClientCAFile != ""))
+		served <- grpcServer.Serve(lis)
+	}()
+
+	joined := make(chan error, 1)
+	go func() {
+		joined <- b.JoinLobby(ctx)
+	}()
+
+	for {
+		select {
+		case err := <-joined:
+			if err != nil {
+				grpcServer.Stop()
+				return err
+			}
+		case err := <-served:
+			return fmt.Errorf("failed to serve gRPC: %w", err)
+		case <-ctx.Done():
+			b.logger.Info("Shutting down gRPC server")
+			grpcServer.GracefulStop()
+			return nil
+		}
+	}
+}
+
+// JoinLobby registers the bot in the server lobby after the configured delay.
+func (b *Bot) JoinLobby(ctx context.Context) error {
+	client, close, err := b.config.ServerClientConfig.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the bot runner gRPC server: %w", err)
+	}
+	defer close()
+
+	request := &pbserver.JoinLobbyRequest{
+		Addr:   b.config.ExternalAddr,
+		Name:   b.config.Name,
+		Secret: b.config.Secret,
+	}
+
+	b.logger.Info(fmt.Sprintf("joining lobby with a delay... addr:%q name:%q signed:%v", request.Addr, request.Name, request.Secret != ""))
+
+	select {
+	case <-time.After(b.config.JoinDelay):
+	case <-ctx.Done():
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	resp, err := client.JoinLobby(ctx, request)
+	b.metrics.ObserveLobbyJoin(err)
+	if err != nil {
+		return fmt.Errorf("failed to join lobby: %w", err)
+	}
+	b.logger.Info(fmt.Sprintf("joined lobby: %v", resp))
+
+	return nil
+}
+
+// Group runs several bots in one process. Bots share the logger, the
+// metrics endpoint and the limit on concurrent strategy computations.
+type Group struct {
+	config   Config
+	logger   *slog.Logger
+	registry *prometheus.Registry
+	limiter  *Limiter
+	bots     []*Bot
+}
+
+func NewGroup(config Config) (*Group, error) {
+	logger, err := NewLogger(os.Stdout, config.LogLevel, config.LogFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Group{}
+	g.config = config
+	g.logger = logger
+	g.registry = NewMetricsRegistry()
+	g.limiter = NewLimiter(config.MaxConcurrency, config.MaxQueueDepth)
+	g.bots = make([]*Bot, 0)
+
+	WatchLimiter(g.registry, g.limiter)
+
+	return g, nil
+}
+
+func (g *Group) Logger() *slog.Logger {
+	return g.logger
+}
+
+// Add creates a bot in the group. Bot config must have a unique name and gRPC address.
+func (g *Group) Add(config Config, placer Placer, shooter Shooter) (*Bot, error) {
+	for _, b := range g.bots {
+		if b.config.Name == config.Name {
+			return nil, fmt.Errorf("bot name %q is already used", config.Name)
+		}
+
+		if !b.config.Reverse && !config.Reverse && b.config.GrpcHost == config.GrpcHost && b.config.GrpcPort == config.GrpcPort {
+			return nil, fmt.Errorf("bot %q gRPC address %v:%v is already used by %q", config.Name, config.GrpcHost, config.GrpcPort, b.config.Name)
+		}
+	}
+
+	bot := newBot(config, placer, shooter, g.logger, g.registry, g.limiter)
+	g.bots = append(g.bots, bot)
+
+	return bot, nil
+}
+
+// Run serves metrics and runs all bots until ctx is done or any bot fails.
+func (g *Group) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	metricsUrl := fmt.Sprintf("%v:%v", g.config.MetricsHost, g.config.MetricsPort)
+	go func() {
+		g.logger.Info(fmt.Sprintf("Starting metrics server at: %v", metricsUrl))
+		if err := ServeMetrics(ctx, metricsUrl, g.registry); err != nil {
+			g.logger.Error(fmt.Sprintf("failed to serve metrics: %v", err))
+		}
+	}()
+
+	errs := make(chan error, len(g.bots))
+
+	for _, bot := range g.bots {
+		go func(bot *Bot) {
+			err := bot.Run(ctx)
+			if err != nil {
+				err = fmt.Errorf("bot %q failed: %w", bot.config.Name, err)
+			}
+
+			errs <- err
+		}(bot)
+	}
+
+	var firstErr error
+
+	for range g.bots {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	return firstErr
+}
+
+// Main runs a bot configured from the config file, environment and flags
+// until it receives SIGINT or SIGTERM, exiting the process on failure.
+func Main(placer Placer, shooter Shooter) {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	MainFunc(config, func(g *Group) error {
+		_, err := g.Add(config, placer, shooter)
+		return err
+	})
+}
+
+// MainFunc runs a group of bots added by setup until the process receives
+// SIGINT or SIGTERM, exiting the process on failure.
+func MainFunc(config Config, setup func(g *Group) error) {
+	g, err := NewGroup(config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create bots group: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := setup(g); err != nil {
+		g.Logger().Error(fmt.Sprintf("failed to set up bots: %v", err))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := g.Run(ctx); err != nil {
+		g.Logger().Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// BotServer implements BattleshipBotService on top of a Placer and a Shooter.
+type BotServer struct {
+	pbbot.UnimplementedBattleshipBotServiceServer
+
+	info     *pbcore.BotInfoProto
+	placer   Placer
+	shooter  Shooter
+	executor *Executor
+	logger   *slog.Logger
+}
+
+func NewBotServer(info *pbcore.BotInfoProto, placer Placer, shooter Shooter, executor *Executor, logger *slog.Logger) *BotServer {
+	b := &BotServer{}
+	b.info = info
+	b.placer = placer
+	b.shooter = shooter
+	b.executor = executor
+	b.logger = logger
+
+	return b
+}
+
+func (b *BotServer) GetInfo(ctx context.Context, request *pbbot.GetInfoRequest) (*pbbot.GetInfoResponse, error) {
+	return &pbbot.GetInfoResponse{Info: b.info}, nil
+}
+
+func (b *BotServer) GetField(ctx context.Context, request *pbbot.GetFieldRequest) (*pbbot.GetFieldResponse, error) {
+	if request.OpponentName != "" {
+		ctx = context.WithValue(ctx, CtxKeyOpponentName, request.OpponentName)
+	}
+
+	b.logger.InfoContext(ctx, "Received GetField request")
+
+	f, err := b.place(ctx, request.GameId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := pbbot.GetFieldResponse{Field: f.ToProto().Field}
+
+	return &resp, nil
+}
+
+func (b *BotServer) GetStrike(ctx context.Context, request *pbbot.GetStrikeRequest) (*pbbot.GetStrikeResponse, error) {
+	b.logger.InfoContext(ctx, "Received GetStrike request")
+
+	own, err := core.NewBattleshipFieldFromProto(request.OwnField)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid own field: %v", err)
+	}
+
+	other := core.NewBattleshipOtherFieldFromProto(request.OtherField)
+
+	pos, err := b.strike(ctx, request.GameId, own, other)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pbbot.GetStrikeResponse{Pos: pos.ToProto()}
+
+	return resp, nil
+}
+
+// place runs the placer within the time budget, falling back to FallbackField.
+func (b *BotServer) place(ctx context.Context, gameId string) (core.BattleshipField, error) {
+	place := func(ctx context.Context, publish func(core.BattleshipField)) error {
+		f, err := b.placer.Place(ctx, gameId)
+		if err != nil {
+			return err
+		}
+
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("placer returned invalid field: %w", err)
+		}
+
+		publish(f)
+
+		return nil
+	}
+
+	f, err := RunAnytime(ctx, b.executor, "GetField", place, FallbackField)
+
+	if err != nil {
+		b.logger.WarnContext(ctx, err.Error())
+		return f, err
+	}
+
+	return f, nil
+}
+
+// strike runs the shooter within the time budget, falling back to
+// FallbackStrikePos if it fails or returns an unavailable position.
+func (b *BotServer) strike(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	shoot := func(ctx context.Context, publish func(core.BattleshipPos)) error {
+		if s, ok := b.shooter.(AnytimeShooter); ok {
+			return s.ShootAnytime(ctx, gameId, own, other, publish)
+		}
+
+		pos, err := b.shooter.Shoot(ctx, gameId, own, other)
+		if err != nil {
+			return err
+		}
+
+		publish(pos)
+
+		return nil
+	}
+
+	fallback := func() (core.BattleshipPos, error) {
+		return FallbackStrikePos(other)
+	}
+
+	pos, err := RunAnytime(ctx, b.executor, "GetStrike", shoot, fallback)
+
+	if err != nil {
+		b.logger.WarnContext(ctx, err.Error())
+		return pos, err
+	}
+
+	if !pos.IsInBounds() || other.Hits.Has(pos) || other.Misses.Has(pos) {
+		b.logger.WarnContext(ctx, fmt.Sprintf("Shooter returned unavailable position %v, using fallback", pos))
+
+		return fallback()
+	}
+
+	return pos, nil
+}
+
+// FallbackField returns a fixed valid field for when placement runs out of time.
+func FallbackField() (core.BattleshipField, error) {
+	f := core.NewBattleshipField()
+
+	for x, ship := range core.BattleshipKinds {
+		for y := 0; y < ship.Size(); y += 1 {
+			f.Field[y][x*2] = core.NewBattleshipTile(ship)
+		}
+	}
+
+	return f, nil
+}
+
+// FallbackStrikePos returns a random position that wasn't struck yet, so
+// it's cheap enough to use when the shooter runs out of time, yet doesn't
+// give up the game: positions next to hits come first, then positions of a
+// checkerboard, which every ship covers at least once.
+func FallbackStrikePos(other core.BattleshipField) (core.BattleshipPos, error) {
+	view := core.NewBattleshipOpponentViewFromField(other)
+	unknown := view.Unknown()
+
+	if len(unknown) == 0 {
+		return core.BattleshipPos{}, NewNoPositionsLeftError()
+	}
+
+	adjacent := make([]core.BattleshipPos, 0)
+	parity := make([]core.BattleshipPos, 0)
+
+	for _, pos := range unknown {
+		for _, n := range []core.BattleshipPos{{X: pos.X + 1, Y: pos.Y}, {X: pos.X - 1, Y: pos.Y}, {X: pos.X, Y: pos.Y + 1}, {X: pos.X, Y: pos.Y - 1}} {
+			if n.IsInBounds() && view.Tile(n) == core.BattleshipOpponentTileHit {
+				adjacent = append(adjacent, pos)
+				break
+			}
+		}
+
+		if (pos.X+pos.Y)%2 == 0 {
+			parity = append(parity, pos)
+		}
+	}
+
+	for _, ps := range [][]core.BattleshipPos{adjacent, parity, unknown} {
+		if len(ps) > 0 {
+			return ps[rand.Intn(len(ps))], nil
+		}
+	}
+
+	return core.BattleshipPos{}, NewNoPositionsLeftError()
+}
@@ -0,0 +1,80 @@
+package botsdk
+
+import (
+	"slices"
+	"testing"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFallbackStrikePos(t *testing.T) {
+	// every position of the field but the given ones is missed
+	missedBut := func(ps ...core.BattleshipPos) core.BattleshipField {
+		f := core.NewBattleshipField()
+
+		for y := 0; y < core.BattleshipFieldSize; y += 1 {
+			for x := 0; x < core.BattleshipFieldSize; x += 1 {
+				pos := core.BattleshipPos{X: x, Y: y}
+
+				if !slices.Contains(ps, pos) {
+					f.Misses.Add(pos)
+				}
+			}
+		}
+
+		return f
+	}
+
+	hitNextTo := core.NewBattleshipField()
+	hitNextTo.Hits.Add(core.BattleshipPos{X: 5, Y: 5})
+	hitNextTo.Misses.Add(core.BattleshipPos{X: 6, Y: 5})
+
+	tests := []struct {
+		name  string
+		field core.BattleshipField
+		valid func(pos core.BattleshipPos) bool
+	}{
+		{
+			"hunts on a checkerboard",
+			core.NewBattleshipField(),
+			func(pos core.BattleshipPos) bool { return (pos.X+pos.Y)%2 == 0 },
+		},
+		{
+			"strikes next to hits",
+			hitNextTo,
+			func(pos core.BattleshipPos) bool {
+				return slices.Contains([]core.BattleshipPos{{X: 4, Y: 5}, {X: 5, Y: 4}, {X: 5, Y: 6}}, pos)
+			},
+		},
+		{
+			"strikes off the checkerboard once it's struck",
+			missedBut(core.BattleshipPos{X: 0, Y: 1}, core.BattleshipPos{X: 9, Y: 8}),
+			func(pos core.BattleshipPos) bool {
+				return pos == core.BattleshipPos{X: 0, Y: 1} || pos == core.BattleshipPos{X: 9, Y: 8}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i += 1 {
+				pos, err := FallbackStrikePos(tt.field)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !tt.valid(pos) {
+					t.Fatalf("unexpected position %v", pos)
+				}
+			}
+		})
+	}
+
+	t.Run("fails once everything is struck", func(t *testing.T) {
+		if _, err := FallbackStrikePos(missedBut()); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("expected %v, got %v", codes.ResourceExhausted, err)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package botsdk
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// BotSpec describes one bot identity of a multi-bot config.
+type BotSpec struct {
+	Name      string `yaml:"name" toml:"name"`
+	Placement string `yaml:"placement,omitempty" toml:"placement,omitempty"`
+	Targeting string `yaml:"targeting,omitempty" toml:"targeting,omitempty"`
+	// PlacementParams and TargetingParams set parameters of tunable strategies.
+	PlacementParams map[string]float64 `yaml:"placement_params,omitempty" toml:"placement_params,omitempty"`
+	TargetingParams map[string]float64 `yaml:"targeting_params,omitempty" toml:"targeting_params,omitempty"`
+	GrpcHost        string             `yaml:"grpc_host,omitempty" toml:"grpc_host,omitempty"`
+	GrpcPort        string             `yaml:"grpc_port" toml:"grpc_port"`
+	ExternalAddr    string             `yaml:"external_addr,omitempty" toml:"external_addr,omitempty"`
+	Secret          string             `yaml:"secret,omitempty" toml:"secret,omitempty" secret:"true"`
+}
+
+// ValidateBotSpecs checks that every bot has a name, a valid port and known
+// strategies. Strategies default to "random".
+func ValidateBotSpecs(specs []BotSpec) error {
+	errs := make([]error, 0)
+
+	for i, spec := range specs {
+		if spec.Name == "" {
+			errs = append(errs, fmt.Errorf("bots[%v].name must not be empty", i))
+		}
+
+		if err := core.ValidatePort(spec.GrpcPort); err != nil {
+			errs = append(errs, fmt.Errorf("bots[%v].grpc_port: %w", i, err))
+		}
+
+		if spec.Secret != "" && len(spec.Secret) < MinSecretLength {
+			errs = append(errs, fmt.Errorf("bots[%v].secret must be at least %v characters long", i, MinSecretLength))
+		}
+
+		if _, err := core.NewPlacementStrategyWithParams(valueOr(spec.Placement, "random"), spec.PlacementParams); err != nil {
+			errs = append(errs, fmt.Errorf("bots[%v].placement: %w", i, err))
+		}
+
+		if _, err := core.NewTargetingStrategyWithParams(valueOr(spec.Targeting, "random"), spec.TargetingParams); err != nil {
+			errs = append(errs, fmt.Errorf("bots[%v].targeting: %w", i, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Config returns the base config with the bot identity and address applied.
+// External address defaults to the base external host with the bot's port,
+// secret defaults to the base secret.
+func (s BotSpec) Config(base Config) Config {
+	c := base
+	c.Name = s.Name
+	c.GrpcPort = s.GrpcPort
+
+	if s.GrpcHost != "" {
+		c.GrpcHost = s.GrpcHost
+	}
+
+	if s.Secret != "" {
+		c.Secret = s.Secret
+	}
+
+	c.ExternalAddr = s.ExternalAddr
+
+	if c.ExternalAddr == "" {
+		host, _, err := net.SplitHostPort(base.ExternalAddr)
+		if err != nil {
+			host = base.ExternalAddr
+		}
+
+		c.ExternalAddr = net.JoinHostPort(host, s.GrpcPort)
+	}
+
+	return c
+}
+
+// AddBots adds every bot spec to the group, looking up its strategies in
+// the core registry. Strategies default to "random".
+func AddBots(g *Group, base Config, specs []BotSpec) error {
+	for _, spec := range specs {
+		placer, shooter, err := NewStrategiesWithParams(valueOr(spec.Placement, "random"), spec.PlacementParams, valueOr(spec.Targeting, "random"), spec.TargetingParams)
+		if err != nil {
+			return fmt.Errorf("bot %q: %w", spec.Name, err)
+		}
+
+		if _, err := g.Add(spec.Config(base), placer, shooter); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+
+	return v
+}
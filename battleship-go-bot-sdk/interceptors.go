@@ -0,0 +1,225 @@
+package botsdk
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+const RequestIdMetadataKey = "x-request-id"
+
+// RequestIdInterceptor propagates the request id received from the caller
+// (or generates a new one) into the context and the response headers.
+func RequestIdInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		requestId := ""
+
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(RequestIdMetadataKey); len(ids) > 0 {
+				requestId = ids[0]
+			}
+		}
+
+		if requestId == "" {
+			requestId = NewRequestId()
+		}
+
+		ctx = context.WithValue(ctx, CtxKeyRequestId, requestId)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIdMetadataKey, requestId))
+
+		return handler(ctx, req)
+	}
+}
+
+// LoggingInterceptor enriches the context with the request details used by
+// ContextHandler and logs the outcome of every request.
+func LoggingInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = context.WithValue(ctx, CtxKeyMethod, MethodName(info.FullMethod))
+
+		if gameId := RequestGameId(ctx, req); gameId != "" {
+			ctx = context.WithValue(ctx, CtxKeyGameId, gameId)
+		}
+
+		if p, ok := peer.FromContext(ctx); ok {
+			ctx = context.WithValue(ctx, CtxKeyPeer, p.Addr.String())
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+
+		level := slog.LevelInfo
+		attrs := []slog.Attr{
+			slog.Duration("Duration", time.Since(start)),
+			slog.String("Code", code.String()),
+		}
+
+		if err != nil {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("Error", status.Convert(err).Message()))
+		}
+
+		logger.LogAttrs(ctx, level, "Handled request", attrs...)
+
+		return resp, err
+	}
+}
+
+// RecoveryInterceptor converts handler panics into codes.Internal errors.
+func RecoveryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.ErrorContext(ctx, fmt.Sprintf("recovered from panic: %v", r), slog.String("Stack", string(debug.Stack())))
+				err = status.Errorf(codes.Internal, "panic while handling request: %v", r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// ctxKeyRequestBody holds the requestBody of a request, set by
+// RequestBodyHandler.
+const ctxKeyRequestBody = CtxKey("RequestBody")
+
+// requestBody is the first message of a request, serialized as received.
+type requestBody struct {
+	data     []byte
+	received bool
+}
+
+// RequestBodyHandler keeps the serialized request of every unary request as
+// it was received, for AuthInterceptor to verify its signature with.
+type RequestBodyHandler struct{}
+
+func (RequestBodyHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestBody, &requestBody{})
+}
+
+// HandleRPC is called with the request before interceptors of unary
+// requests, and with every received message of streams.
+func (RequestBodyHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	p, ok := s.(*stats.InPayload)
+	if !ok {
+		return
+	}
+
+	if b, ok := ctx.Value(ctxKeyRequestBody).(*requestBody); ok && !b.received {
+		b.data = bytes.Clone(p.Data)
+		b.received = true
+	}
+}
+
+func (RequestBodyHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (RequestBodyHandler) HandleConn(ctx context.Context, s stats.ConnStats) {}
+
+// AuthInterceptor rejects bot service requests without a valid server
+// signature made with secret within maxSkew, see core.SignBotRequest.
+// Bodies of unary requests are kept by RequestBodyHandler, which must be
+// set as a stats handler of the server. Only the stream setup is verified
+// for PlayGame, messages sent over the stream aren't signed.
+func AuthInterceptor(secret string, maxSkew time.Duration) grpc.UnaryServerInterceptor {
+	service := "/" + pbbot.BattleshipBotService_ServiceDesc.ServiceName + "/"
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, service) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		timestamps := md.Get(core.SignatureTimestampMetadataKey)
+		signatures := md.Get(core.SignatureMetadataKey)
+
+		if len(timestamps) == 0 || len(signatures) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "request is not signed")
+		}
+
+		// streams are verified once with a nil request, before any message
+		var body []byte
+
+		if req != nil {
+			b, ok := ctx.Value(ctxKeyRequestBody).(*requestBody)
+			if !ok || !b.received {
+				return nil, status.Error(codes.Internal, "request body to verify the signature with wasn't kept")
+			}
+
+			body = b.data
+		}
+
+		if err := core.VerifyBotRequestSignature(secret, info.FullMethod, RequestGameId(ctx, req), body, timestamps[0], signatures[0], time.Now(), maxSkew); err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "failed to verify request signature: %v", err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor adapts a unary interceptor to streams, so the same
+// interceptors cover PlayGame. The interceptor is called once per stream
+// with a nil request, and the context it passes on is used by the stream.
+func StreamInterceptor(interceptor grpc.UnaryServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		unaryInfo := &grpc.UnaryServerInfo{Server: srv, FullMethod: info.FullMethod}
+
+		_, err := interceptor(ss.Context(), nil, unaryInfo, func(ctx context.Context, _ any) (any, error) {
+			return nil, handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		})
+
+		return err
+	}
+}
+
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// RequestGameId returns the game id of the request, or for streams, the game
+// id sent in core.GameIdMetadataKey header.
+func RequestGameId(ctx context.Context, req any) string {
+	if r, ok := req.(interface{ GetGameId() string }); ok {
+		return r.GetGameId()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(core.GameIdMetadataKey); len(ids) > 0 {
+			return ids[0]
+		}
+	}
+
+	return ""
+}
+
+func NewRequestId() string {
+	b := make([]byte, 8)
+
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(b)
+}
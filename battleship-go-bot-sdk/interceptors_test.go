@@ -0,0 +1,111 @@
+package botsdk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	_ "github.com/mtratsiuk/battleship/battleship-go-strategy"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// rawCodec sends requests serialized by the caller as they are.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	return v.([]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	return proto.Unmarshal(data, v.(proto.Message))
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func TestAuthInterceptorVerifiesReceivedBody(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	config := NewConfig()
+	config.Secret = secret
+
+	placer, shooter, err := NewStrategies("random", "random")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bot := newBot(config, placer, shooter, logger, prometheus.NewRegistry(), NewLimiter(10, 10))
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := bot.NewGrpcServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}
+
+	conn, err := grpc.Dial("bufconn", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	t.Run("driver", func(t *testing.T) {
+		driver := core.NewBotPlayerDriver(pbbot.NewBattleshipBotServiceClient(conn), secret, time.Second)
+
+		if _, err := driver.Field(context.Background(), "game"); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// a field unknown to the bot, serialized before known ones, isn't kept
+	// in place if the bot serializes the parsed request again
+	known, _ := proto.Marshal(&pbbot.GetFieldRequest{GameId: "game", OpponentName: "Opponent"})
+	body := protowire.AppendVarint(protowire.AppendTag(nil, 15, protowire.VarintType), 1)
+	body = append(body, known...)
+
+	method := pbbot.BattleshipBotService_GetField_FullMethodName
+
+	tests := []struct {
+		name     string
+		signed   []byte
+		expected codes.Code
+	}{
+		{"body with unknown fields", body, codes.OK},
+		{"other body", known, codes.Unauthenticated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now := time.Now()
+			ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs(
+				core.SignatureTimestampMetadataKey, strconv.FormatInt(now.UnixMilli(), 10),
+				core.SignatureMetadataKey, core.SignBotRequest(secret, method, "game", now.UnixMilli(), test.signed),
+			))
+
+			resp := &pbbot.GetFieldResponse{}
+			err := conn.Invoke(ctx, method, body, resp, grpc.ForceCodec(rawCodec{}))
+
+			if code := status.Code(err); code != test.expected {
+				t.Fatalf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrQueueFull = errors.New("strategy queue is full")
+
+// Limiter bounds the number of concurrent strategy computations. Waiting
+// computations are queued per game and served round-robin across games, so a
+// single game issuing many requests can't starve the others.
+type Limiter struct {
+	mu       sync.Mutex
+	free     int
+	limit    int
+	maxQueue int
+	depth    int
+	queues   map[string][]*limiterWaiter
+	order    []string
+}
+
+type limiterWaiter struct {
+	ready chan struct{}
+}
+
+func NewLimiter(limit, maxQueue int) *Limiter {
+	l := &Limiter{}
+	l.free = limit
+	l.limit = limit
+	l.maxQueue = maxQueue
+	l.queues = make(map[string][]*limiterWaiter)
+	l.order = make([]string, 0)
+
+	return l
+}
+
+// Acquire blocks until a computation slot is available for the game or ctx
+// is done. ErrQueueFull is returned immediately if too many computations are
+// already waiting. The returned release function must be called once the
+// computation finishes.
+func (l *Limiter) Acquire(ctx context.Context, gameId string) (func(), error) {
+	l.mu.Lock()
+
+	if l.free > 0 && l.depth == 0 {
+		l.free -= 1
+		l.mu.Unlock()
+		return l.release, nil
+	}
+
+	if l.depth >= l.maxQueue {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+
+	w := &limiterWaiter{make(chan struct{})}
+
+	if _, ok := l.queues[gameId]; !ok {
+		l.order = append(l.order, gameId)
+	}
+	l.queues[gameId] = append(l.queues[gameId], w)
+	l.depth += 1
+
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return l.release, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if l.remove(gameId, w) {
+			return nil, ctx.Err()
+		}
+
+		// Slot was handed over concurrently with cancellation, pass it on.
+		l.releaseLocked()
+
+		return nil, ctx.Err()
+	}
+}
+
+// Depth returns the number of computations waiting for a slot.
+func (l *Limiter) Depth() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.depth
+}
+
+// InFlight returns the number of running computations.
+func (l *Limiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limit - l.free
+}
+
+func (l *Limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.releaseLocked()
+}
+
+func (l *Limiter) releaseLocked() {
+	if l.depth == 0 {
+		l.free += 1
+		return
+	}
+
+	gameId := l.order[0]
+	queue := l.queues[gameId]
+	w := queue[0]
+
+	l.order = l.order[1:]
+	l.depth -= 1
+
+	if len(queue) > 1 {
+		l.queues[gameId] = queue[1:]
+		l.order = append(l.order, gameId)
+	} else {
+		delete(l.queues, gameId)
+	}
+
+	close(w.ready)
+}
+
+func (l *Limiter) remove(gameId string, w *limiterWaiter) bool {
+	queue := l.queues[gameId]
+
+	for i, cur := range queue {
+		if cur != w {
+			continue
+		}
+
+		l.depth -= 1
+
+		if len(queue) > 1 {
+			l.queues[gameId] = append(queue[:i:i], queue[i+1:]...)
+			return true
+		}
+
+		delete(l.queues, gameId)
+
+		for j, id := range l.order {
+			if id == gameId {
+				l.order = append(l.order[:j:j], l.order[j+1:]...)
+				break
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
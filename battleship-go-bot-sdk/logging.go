@@ -0,0 +1,81 @@
+package botsdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type CtxKey string
+
+const (
+	CtxKeyMethod    = CtxKey("Method")
+	CtxKeyGameId    = CtxKey("GameId")
+	CtxKeyRequestId = CtxKey("RequestId")
+	CtxKeyPeer      = CtxKey("Peer")
+	// CtxKeyOpponentName is set for GetField and PlayGame if the server
+	// tells the opponent's name, see OpponentName.
+	CtxKeyOpponentName = CtxKey("OpponentName")
+)
+
+// CtxLogKeys are the context keys whose values are added to every log
+// record emitted with a *Context logging method.
+var CtxLogKeys = []CtxKey{
+	CtxKeyMethod,
+	CtxKeyGameId,
+	CtxKeyRequestId,
+	CtxKeyPeer,
+	CtxKeyOpponentName,
+}
+
+type ContextHandler struct {
+	slog.Handler
+}
+
+func NewContextHandler(h slog.Handler) ContextHandler {
+	return ContextHandler{h}
+}
+
+func (h ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	for _, key := range CtxLogKeys {
+		if v := ctx.Value(key); v != nil {
+			r.AddAttrs(slog.Any(string(key), v))
+		}
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return ContextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h ContextHandler) WithGroup(name string) slog.Handler {
+	return ContextHandler{h.Handler.WithGroup(name)}
+}
+
+// NewLogger creates a context-aware logger writing records of at least the
+// given level ("debug", "info", "warn" or "error") in the given format
+// ("json" or "text").
+func NewLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("unexpected log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: l}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unexpected log format %q, expected json or text", format)
+	}
+
+	return slog.New(NewContextHandler(h)), nil
+}
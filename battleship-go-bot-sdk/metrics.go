@@ -0,0 +1,265 @@
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	MetricsNamespace = "battleship_bot"
+
+	// Bot never learns that a game is over, so a game is considered active
+	// until no requests were received for it during this period.
+	ActiveGameIdleTimeout = time.Minute
+)
+
+type Metrics struct {
+	requests         *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	errors           *prometheus.CounterVec
+	strategyDuration *prometheus.HistogramVec
+	strategyOutcomes *prometheus.CounterVec
+	lobbyRegistered  prometheus.Gauge
+	lobbyJoins       *prometheus.CounterVec
+	degradations     *prometheus.CounterVec
+
+	games *ActiveGames
+}
+
+// NewMetricsRegistry creates a registry with Go runtime and process metrics,
+// shared by all bots of the process.
+func NewMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
+
+// NewMetrics registers metrics of a single bot, labeled with the bot name.
+func NewMetrics(registerer prometheus.Registerer, botName string) *Metrics {
+	m := &Metrics{}
+	m.games = NewActiveGames(ActiveGameIdleTimeout)
+
+	m.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "requests_total",
+		Help:      "Number of handled gRPC requests by method.",
+	}, []string{"method"})
+
+	m.requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: MetricsNamespace,
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request handling latency by method.",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 10),
+	}, []string{"method"})
+
+	m.errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "errors_total",
+		Help:      "Number of failed gRPC requests by method and status code.",
+	}, []string{"method", "code"})
+
+	m.strategyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: MetricsNamespace,
+		Name:      "strategy_duration_seconds",
+		Help:      "Time spent by the strategy to make a decision by method.",
+		Buckets:   prometheus.ExponentialBuckets(0.00001, 4, 10),
+	}, []string{"method"})
+
+	m.strategyOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "strategy_outcomes_total",
+		Help:      "Number of strategy runs by method and outcome (completed, failed, best or fallback).",
+	}, []string{"method", "outcome"})
+
+	m.lobbyRegistered = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "lobby_registered",
+		Help:      "Whether the bot is registered in the server lobby (1) or not (0).",
+	})
+
+	m.lobbyJoins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "lobby_joins_total",
+		Help:      "Number of attempts to join the server lobby by status code.",
+	}, []string{"code"})
+
+	m.degradations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "strategy_degradations_total",
+		Help:      "Number of strategy runs replaced by the fallback strategy due to load by method and reason.",
+	}, []string{"method", "reason"})
+
+	activeGames := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "active_games",
+		Help:      "Number of games with requests received during the last minute.",
+	}, func() float64 { return float64(m.games.Count()) })
+
+	info := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Name:      "info",
+		Help:      "Bot identity, always 1.",
+	})
+	info.Set(1)
+
+	prometheus.WrapRegistererWith(prometheus.Labels{"bot": botName}, registerer).MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.errors,
+		m.strategyDuration,
+		m.strategyOutcomes,
+		m.lobbyRegistered,
+		m.lobbyJoins,
+		m.degradations,
+		activeGames,
+		info,
+	)
+
+	return m
+}
+
+// ServeMetrics serves metrics from the registry at /metrics until ctx is done.
+func ServeMetrics(ctx context.Context, addr string, registry *prometheus.Registry) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}))
+
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// WatchLimiter registers queue depth and in-flight computations metrics of the limiter.
+func WatchLimiter(registerer prometheus.Registerer, l *Limiter) {
+	registerer.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "strategy_queue_depth",
+			Help:      "Number of strategy computations waiting for a slot.",
+		}, func() float64 { return float64(l.Depth()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      "strategy_in_flight",
+			Help:      "Number of running strategy computations.",
+		}, func() float64 { return float64(l.InFlight()) }),
+	)
+}
+
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := MethodName(info.FullMethod)
+
+		if gameId := RequestGameId(ctx, req); gameId != "" {
+			m.games.Touch(gameId)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		m.requests.WithLabelValues(method).Inc()
+		m.requestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			m.errors.WithLabelValues(method, status.Code(err).String()).Inc()
+		}
+
+		return resp, err
+	}
+}
+
+func (m *Metrics) ObserveStrategy(method string, start time.Time) {
+	m.strategyDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) ObserveAnytime(method, outcome string) {
+	m.strategyOutcomes.WithLabelValues(method, outcome).Inc()
+}
+
+func (m *Metrics) ObserveDegradation(method, reason string) {
+	m.degradations.WithLabelValues(method, reason).Inc()
+}
+
+func (m *Metrics) ObserveLobbyJoin(err error) {
+	m.lobbyJoins.WithLabelValues(status.Code(err).String()).Inc()
+
+	if err == nil {
+		m.lobbyRegistered.Set(1)
+	} else {
+		m.lobbyRegistered.Set(0)
+	}
+}
+
+// MethodName returns the short method name of a full gRPC method,
+// e.g. "GetField" for "/battleship.proto.bot.v1.BattleshipBotService/GetField".
+func MethodName(fullMethod string) string {
+	for i := len(fullMethod) - 1; i >= 0; i -= 1 {
+		if fullMethod[i] == '/' {
+			return fullMethod[i+1:]
+		}
+	}
+
+	return fullMethod
+}
+
+type ActiveGames struct {
+	mu          sync.Mutex
+	idleTimeout time.Duration
+	lastSeen    map[string]time.Time
+}
+
+func NewActiveGames(idleTimeout time.Duration) *ActiveGames {
+	a := &ActiveGames{}
+	a.idleTimeout = idleTimeout
+	a.lastSeen = make(map[string]time.Time)
+
+	return a
+}
+
+func (a *ActiveGames) Touch(gameId string) {
+	if gameId == "" {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.lastSeen[gameId] = time.Now()
+}
+
+func (a *ActiveGames) Count() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+
+	for id, seen := range a.lastSeen {
+		if now.Sub(seen) > a.idleTimeout {
+			delete(a.lastSeen, id)
+		}
+	}
+
+	return len(a.lastSeen)
+}
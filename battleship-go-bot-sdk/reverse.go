@@ -0,0 +1,180 @@
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RunReverse joins the lobby over the server's Connect stream and serves bot
+// requests received over it, so the server doesn't have to reach the bot.
+// Lost connections are reestablished after the reconnect delay, until ctx is
+// done or the server rejects the bot.
+func (b *Bot) RunReverse(ctx context.Context) error {
+	client, close, err := b.config.ServerClientConfig.NewClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the bot runner gRPC server: %w", err)
+	}
+	defer close()
+
+	b.logger.Info(fmt.Sprintf("joining lobby over reverse connection with a delay... name:%q signed:%v", b.config.Name, b.config.Secret != ""))
+
+	select {
+	case <-time.After(b.config.JoinDelay):
+	case <-ctx.Done():
+		return nil
+	}
+
+	handler := ChainUnaryInterceptors(append(b.interceptors(), ValidationInterceptor())...)
+
+	for {
+		err := b.serveReverse(ctx, client, handler)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		switch status.Code(err) {
+		case codes.InvalidArgument, codes.AlreadyExists, codes.PermissionDenied, codes.Unauthenticated, codes.Unimplemented:
+			return fmt.Errorf("failed to join lobby: %w", err)
+		}
+
+		b.logger.Warn(fmt.Sprintf("reverse connection is lost, reconnecting in %v: %v", b.config.ReconnectDelay, err))
+
+		select {
+		case <-time.After(b.config.ReconnectDelay):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (b *Bot) serveReverse(ctx context.Context, client pbserver.BattleshipServerServiceClient, handler grpc.UnaryServerInterceptor) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	// cancelled before waiting for requests in flight
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	join := &pbserver.JoinLobbyRequest{Name: b.config.Name, Secret: b.config.Secret}
+	if err := stream.Send(&pbserver.ConnectRequest{Message: &pbserver.ConnectRequest_Join{Join: join}}); err != nil {
+		return err
+	}
+
+	ack, err := stream.Recv()
+	b.metrics.ObserveLobbyJoin(err)
+	if err != nil {
+		return err
+	}
+	if ack.Request != nil {
+		return errors.New("expected lobby join to be acknowledged")
+	}
+
+	b.logger.Info("joined lobby over reverse connection")
+	defer b.metrics.ObserveLobbyJoin(status.Error(codes.Unavailable, "reverse connection is lost"))
+
+	var sendMu sync.Mutex
+
+	for {
+		request, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			response := b.handleReverse(ctx, handler, request)
+
+			sendMu.Lock()
+			defer sendMu.Unlock()
+
+			if err := stream.Send(&pbserver.ConnectRequest{Message: &pbserver.ConnectRequest_Response{Response: response}}); err != nil {
+				b.logger.Warn(fmt.Sprintf("failed to send reverse connection response: %v", err))
+			}
+		}()
+	}
+}
+
+// handleReverse runs a request received over the reverse connection through
+// the same interceptors and handlers as requests served over gRPC, besides
+// signature verification, as the connection is made by the bot itself.
+func (b *Bot) handleReverse(ctx context.Context, handler grpc.UnaryServerInterceptor, request *pbserver.ConnectResponse) *pbserver.ConnectBotResponse {
+	response := &pbserver.ConnectBotResponse{RequestId: request.RequestId}
+
+	var req any
+	var method string
+	var call grpc.UnaryHandler
+
+	switch r := request.Request.(type) {
+	case *pbserver.ConnectResponse_GetField:
+		req, method = r.GetField, pbbot.BattleshipBotService_GetField_FullMethodName
+		call = func(ctx context.Context, req any) (any, error) {
+			return b.server.GetField(ctx, req.(*pbbot.GetFieldRequest))
+		}
+	case *pbserver.ConnectResponse_GetStrike:
+		req, method = r.GetStrike, pbbot.BattleshipBotService_GetStrike_FullMethodName
+		call = func(ctx context.Context, req any) (any, error) {
+			return b.server.GetStrike(ctx, req.(*pbbot.GetStrikeRequest))
+		}
+	case *pbserver.ConnectResponse_GetInfo:
+		req, method = r.GetInfo, pbbot.BattleshipBotService_GetInfo_FullMethodName
+		call = func(ctx context.Context, req any) (any, error) {
+			return b.server.GetInfo(ctx, req.(*pbbot.GetInfoRequest))
+		}
+	default:
+		response.Result = &pbserver.ConnectBotResponse_Error{Error: &pbserver.ConnectBotError{Code: uint32(codes.Unimplemented), Message: "unknown request"}}
+		return response
+	}
+
+	resp, err := handler(ctx, req, &grpc.UnaryServerInfo{Server: b.server, FullMethod: method}, call)
+
+	if err != nil {
+		s := status.Convert(err)
+		response.Result = &pbserver.ConnectBotResponse_Error{Error: &pbserver.ConnectBotError{Code: uint32(s.Code()), Message: s.Message()}}
+		return response
+	}
+
+	switch r := resp.(type) {
+	case *pbbot.GetFieldResponse:
+		response.Result = &pbserver.ConnectBotResponse_GetField{GetField: r}
+	case *pbbot.GetStrikeResponse:
+		response.Result = &pbserver.ConnectBotResponse_GetStrike{GetStrike: r}
+	case *pbbot.GetInfoResponse:
+		response.Result = &pbserver.ConnectBotResponse_GetInfo{GetInfo: r}
+	}
+
+	return response
+}
+
+// ChainUnaryInterceptors chains interceptors the same way as
+// grpc.ChainUnaryInterceptor, for requests not served by a grpc.Server.
+func ChainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		chained := handler
+
+		for i := len(interceptors) - 1; i >= 0; i -= 1 {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+
+		return chained(ctx, req)
+	}
+}
@@ -0,0 +1,60 @@
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// StrategyPlacer adapts a core placement strategy to the Placer interface.
+type StrategyPlacer struct {
+	Strategy core.BattleshipPlacementStrategy
+}
+
+func (s StrategyPlacer) Place(ctx context.Context, gameId string) (core.BattleshipField, error) {
+	return s.Strategy.Place(ctx, NewRand())
+}
+
+// StrategyShooter adapts a core targeting strategy to the Shooter interface.
+type StrategyShooter struct {
+	Strategy core.BattleshipTargetingStrategy
+}
+
+func (s StrategyShooter) Shoot(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	view := core.NewBattleshipOpponentViewFromField(other)
+
+	pos, err := s.Strategy.Target(ctx, &view, NewRand())
+	if errors.Is(err, core.ErrNoPositionsLeft) {
+		return pos, NewNoPositionsLeftError()
+	}
+
+	return pos, err
+}
+
+// NewStrategies looks up placement and targeting strategies by name in the core registry.
+func NewStrategies(placement, targeting string) (StrategyPlacer, StrategyShooter, error) {
+	return NewStrategiesWithParams(placement, nil, targeting, nil)
+}
+
+// NewStrategiesWithParams looks up placement and targeting strategies by
+// name in the core registry and sets their parameters, e.g. tuned ones.
+func NewStrategiesWithParams(placement string, placementParams map[string]float64, targeting string, targetingParams map[string]float64) (StrategyPlacer, StrategyShooter, error) {
+	p, err := core.NewPlacementStrategyWithParams(placement, placementParams)
+	if err != nil {
+		return StrategyPlacer{}, StrategyShooter{}, err
+	}
+
+	t, err := core.NewTargetingStrategyWithParams(targeting, targetingParams)
+	if err != nil {
+		return StrategyPlacer{}, StrategyShooter{}, err
+	}
+
+	return StrategyPlacer{p}, StrategyShooter{t}, nil
+}
+
+func NewRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
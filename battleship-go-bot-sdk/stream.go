@@ -0,0 +1,164 @@
+package botsdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GameSession is the state of a game played over PlayGame. Unlike the unary
+// API, fields aren't sent with every turn, so the session tracks them from
+// strike outcomes.
+type GameSession struct {
+	GameId string
+	Own    core.BattleshipField
+	Other  core.BattleshipField
+}
+
+func NewGameSession(gameId string, own core.BattleshipField) *GameSession {
+	s := &GameSession{}
+	s.GameId = gameId
+	s.Own = own
+	s.Other = core.NewBattleshipField()
+
+	return s
+}
+
+// ApplyOutcome records the outcome of a strike made by either player.
+func (s *GameSession) ApplyOutcome(e *pbbot.StrikeOutcomeEvent) {
+	pos := core.NewBattleshipPosFromProto(e.Pos)
+
+	if !e.Own {
+		s.Own.Strike(pos)
+		return
+	}
+
+	if e.Hit {
+		s.Other.Hits.Add(pos)
+	} else {
+		s.Other.Misses.Add(pos)
+	}
+}
+
+// PlayGame plays a single game over the stream, see GetField and GetStrike
+// for the unary equivalents.
+func (b *BotServer) PlayGame(stream pbbot.BattleshipBotService_PlayGameServer) error {
+	ctx := stream.Context()
+
+	var session *GameSession
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch e := req.Event.(type) {
+		case *pbbot.PlayGameRequest_GameStart:
+			if session != nil {
+				return status.Error(codes.FailedPrecondition, "game is already started")
+			}
+
+			if e.GameStart.GameId == "" {
+				return status.Error(codes.InvalidArgument, "game_id must not be empty")
+			}
+
+			if gameId := RequestGameId(ctx, nil); gameId != "" && gameId != e.GameStart.GameId {
+				return status.Errorf(codes.InvalidArgument, "game_id %q doesn't match %v header", e.GameStart.GameId, core.GameIdMetadataKey)
+			}
+
+			ctx = context.WithValue(ctx, CtxKeyGameId, e.GameStart.GameId)
+			if e.GameStart.OpponentName != "" {
+				ctx = context.WithValue(ctx, CtxKeyOpponentName, e.GameStart.OpponentName)
+			}
+
+			b.logger.InfoContext(ctx, "Received GameStart event")
+
+			f, err := b.place(ctx, e.GameStart.GameId)
+			if err != nil {
+				return err
+			}
+
+			session = NewGameSession(e.GameStart.GameId, f)
+
+			if err := stream.Send(&pbbot.PlayGameResponse{Action: &pbbot.PlayGameResponse_Field{Field: f.ToProto().Field}}); err != nil {
+				return err
+			}
+		case *pbbot.PlayGameRequest_Turn:
+			if session == nil {
+				return status.Error(codes.FailedPrecondition, "game is not started")
+			}
+
+			pos, err := b.strike(ctx, session.GameId, session.Own, session.Other)
+			if err != nil {
+				return err
+			}
+
+			if err := stream.Send(&pbbot.PlayGameResponse{Action: &pbbot.PlayGameResponse_Strike{Strike: pos.ToProto()}}); err != nil {
+				return err
+			}
+		case *pbbot.PlayGameRequest_StrikeOutcome:
+			if session == nil {
+				return status.Error(codes.FailedPrecondition, "game is not started")
+			}
+
+			if e.StrikeOutcome.Pos == nil || !core.NewBattleshipPosFromProto(e.StrikeOutcome.Pos).IsInBounds() {
+				return status.Error(codes.InvalidArgument, "strike_outcome.pos must be within the field")
+			}
+
+			session.ApplyOutcome(e.StrikeOutcome)
+			b.strikeOutcome(ctx, session.GameId, e.StrikeOutcome)
+		case *pbbot.PlayGameRequest_GameOver:
+			b.logger.InfoContext(ctx, fmt.Sprintf("Game is over, won: %v", e.GameOver.Won))
+
+			if session != nil {
+				b.gameOver(ctx, session.GameId, e.GameOver)
+			}
+
+			return nil
+		default:
+			return status.Error(codes.InvalidArgument, "event must be set")
+		}
+	}
+}
+
+// strikeOutcome notifies the placer and the shooter implementing
+// StrikeOutcomeObserver.
+func (b *BotServer) strikeOutcome(ctx context.Context, gameId string, e *pbbot.StrikeOutcomeEvent) {
+	pos := core.NewBattleshipPosFromProto(e.Pos)
+
+	for _, s := range []any{b.placer, b.shooter} {
+		if o, ok := s.(StrikeOutcomeObserver); ok {
+			o.StrikeOutcome(ctx, gameId, pos, e.Own, e.Hit)
+		}
+	}
+}
+
+// gameOver notifies the placer and the shooter implementing GameOverObserver.
+func (b *BotServer) gameOver(ctx context.Context, gameId string, e *pbbot.GameOverEvent) {
+	var opponent *core.BattleshipField
+
+	if e.OpponentField != nil {
+		f, err := core.NewBattleshipFieldFromProto(e.OpponentField)
+		if err != nil {
+			b.logger.WarnContext(ctx, fmt.Sprintf("Ignoring invalid opponent field: %v", err))
+		} else {
+			opponent = &f
+		}
+	}
+
+	for _, s := range []any{b.placer, b.shooter} {
+		if o, ok := s.(GameOverObserver); ok {
+			o.GameOver(ctx, gameId, e.Won, opponent)
+		}
+	}
+}
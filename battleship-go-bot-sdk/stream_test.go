@@ -0,0 +1,146 @@
+package botsdk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type observedOutcome struct {
+	pos core.BattleshipPos
+	own bool
+	hit bool
+}
+
+// observingShooter records what the bot is told about the game.
+type observingShooter struct {
+	StrategyShooter
+
+	mu       sync.Mutex
+	outcomes []observedOutcome
+	over     int
+	won      bool
+	opponent *core.BattleshipField
+}
+
+func (s *observingShooter) StrikeOutcome(ctx context.Context, gameId string, pos core.BattleshipPos, own, hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.outcomes = append(s.outcomes, observedOutcome{pos, own, hit})
+}
+
+func (s *observingShooter) GameOver(ctx context.Context, gameId string, won bool, opponent *core.BattleshipField) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.over += 1
+	s.won = won
+	s.opponent = opponent
+}
+
+func TestStreamingPlayerDriver(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	config := NewConfig()
+	config.Secret = secret
+
+	placer, strategyShooter, err := NewStrategies("random", "random")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shooter := &observingShooter{StrategyShooter: strategyShooter}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	bot := newBot(config, placer, shooter, logger, prometheus.NewRegistry(), NewLimiter(10, 10))
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := bot.NewGrpcServer()
+	go server.Serve(listener)
+	defer server.Stop()
+
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}
+
+	conn, err := grpc.Dial("bufconn", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	placement, err := core.NewPlacementStrategy("random")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targeting, err := core.NewTargetingStrategy("random")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := core.NewBattleshipGame("game", "bot", "opponent")
+	players := map[string]core.PlayerDriver{
+		"bot":      core.NewStreamingPlayerDriver(core.NewBotPlayerDriver(pbbot.NewBattleshipBotServiceClient(conn), secret, time.Second)),
+		"opponent": core.NewStrategyPlayerDriver(placement, targeting, rand.New(rand.NewSource(1))),
+	}
+
+	if err := core.PlayBattleshipGame(context.Background(), game, players); err != nil {
+		t.Fatal(err)
+	}
+
+	winnerId, _ := game.WinnerId()
+	opponent := game.PlayerField("opponent")
+	own := game.PlayerField("bot")
+
+	shooter.mu.Lock()
+	defer shooter.mu.Unlock()
+
+	if shooter.over != 1 {
+		t.Fatalf("expected game over to be observed once, got %v", shooter.over)
+	}
+
+	if shooter.won != (winnerId == "bot") {
+		t.Errorf("expected won to be %v, got %v", winnerId == "bot", shooter.won)
+	}
+
+	if shooter.opponent == nil {
+		t.Fatal("expected opponent field to be revealed")
+	}
+
+	if got, want := shooter.opponent.ToProto().Field, opponent.ToProto().Field; got != want {
+		t.Errorf("expected opponent field\n%v\ngot\n%v", want, got)
+	}
+
+	counts := map[bool]int{}
+
+	for _, o := range shooter.outcomes {
+		counts[o.own] += 1
+
+		field := own
+		if o.own {
+			field = opponent
+		}
+
+		if hit := !field.Field[o.pos.Y][o.pos.X].IsEmpty(); o.hit != hit {
+			t.Errorf("expected outcome of %v (own: %v) to be hit: %v", o.pos, o.own, hit)
+		}
+	}
+
+	if counts[true] == 0 || counts[false] == 0 {
+		t.Errorf("expected outcomes of strikes of both players, got %v", counts)
+	}
+}
@@ -0,0 +1,169 @@
+package botsdk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// CertReloadCheckInterval is how often certificate files are checked for changes.
+const CertReloadCheckInterval = time.Second
+
+// CertReloader serves a certificate and client CAs loaded from files, and
+// reloads them when the files change, so certificates can be rotated without
+// a restart. Files are checked during TLS handshakes, at most once per
+// CertReloadCheckInterval. If reloading fails, previous certificates are kept.
+type CertReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  []time.Time
+	checkedAt time.Time
+}
+
+// NewCertReloader loads the certificate and, if clientCAFile is set, the
+// CA certificates client certificates are verified against.
+func NewCertReloader(certFile, keyFile, clientCAFile string, logger *slog.Logger) (*CertReloader, error) {
+	r := &CertReloader{}
+	r.certFile = certFile
+	r.keyFile = keyFile
+	r.clientCAFile = clientCAFile
+	r.logger = logger
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// TLSConfig returns a server TLS config using the current certificates.
+// When client CAs are configured, clients must present a certificate signed
+// by them and, if clientName is set, issued to clientName.
+func (r *CertReloader) TLSConfig(clientName string) *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		cert, clientCAs := r.current()
+
+		c := &tls.Config{MinVersion: tls.VersionTLS12}
+		c.Certificates = []tls.Certificate{*cert}
+		c.NextProtos = []string{"h2"}
+
+		if clientCAs != nil {
+			c.ClientCAs = clientCAs
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+
+			if clientName != "" {
+				c.VerifyPeerCertificate = verifyClientName(clientName)
+			}
+		}
+
+		return c, nil
+	}
+
+	return config
+}
+
+func (r *CertReloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) >= CertReloadCheckInterval {
+		r.checkedAt = time.Now()
+
+		if modTimes, err := r.readModTimes(); err == nil && !slices.Equal(modTimes, r.modTimes) {
+			if err := r.load(); err != nil {
+				r.logger.Error(fmt.Sprintf("failed to reload TLS certificates, keeping previous ones: %v", err))
+			} else {
+				r.logger.Info("Reloaded TLS certificates")
+			}
+		}
+	}
+
+	return r.cert, r.clientCAs
+}
+
+func (r *CertReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkedAt = time.Now()
+
+	return r.load()
+}
+
+func (r *CertReloader) load() error {
+	modTimes, err := r.readModTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+
+	if r.clientCAFile != "" {
+		if clientCAs, err = core.LoadCertPool(r.clientCAFile); err != nil {
+			return err
+		}
+	}
+
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+
+	return nil
+}
+
+func (r *CertReloader) readModTimes() ([]time.Time, error) {
+	modTimes := make([]time.Time, 0, 3)
+
+	for _, path := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	return modTimes, nil
+}
+
+func verifyClientName(name string) func([][]byte, [][]*x509.Certificate) error {
+	return func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		for _, chain := range verifiedChains {
+			if len(chain) == 0 {
+				continue
+			}
+
+			leaf := chain[0]
+			if leaf.Subject.CommonName == name || slices.Contains(leaf.DNSNames, name) {
+				return nil
+			}
+		}
+
+		return errors.New("client certificate is not issued to " + name)
+	}
+}
@@ -0,0 +1,189 @@
+package botsdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+const (
+	ErrorInfoDomain = "battleship.bot"
+
+	ErrorReasonInvalidRequest  = "INVALID_REQUEST"
+	ErrorReasonGameOver        = "GAME_OVER"
+	ErrorReasonNoPositionsLeft = "NO_POSITIONS_LEFT"
+)
+
+// ValidationInterceptor rejects malformed requests before they reach the handler.
+func ValidationInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var err error
+
+		switch r := req.(type) {
+		case *pbbot.GetFieldRequest:
+			err = ValidateGetFieldRequest(r)
+		case *pbbot.GetStrikeRequest:
+			err = ValidateGetStrikeRequest(r)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func ValidateGetFieldRequest(r *pbbot.GetFieldRequest) error {
+	v := NewViolations()
+
+	if r.GameId == "" {
+		v.Add("game_id", "must not be empty")
+	}
+
+	return v.Err()
+}
+
+func ValidateGetStrikeRequest(r *pbbot.GetStrikeRequest) error {
+	v := NewViolations()
+
+	if r.GameId == "" {
+		v.Add("game_id", "must not be empty")
+	}
+
+	if r.OwnField == nil {
+		v.Add("own_field", "must be present")
+	} else {
+		if f, err := core.NewBattleshipFieldFromProto(r.OwnField); err != nil {
+			v.Add("own_field.field", err.Error())
+		} else if err := f.Validate(); err != nil {
+			v.Add("own_field.field", err.Error())
+		}
+
+		v.AddPositions("own_field", r.OwnField.Hits, r.OwnField.Misses)
+	}
+
+	if r.OtherField == nil {
+		v.Add("other_field", "must be present")
+	} else {
+		v.AddPositions("other_field", r.OtherField.Hits, r.OtherField.Misses)
+	}
+
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	if len(r.OwnField.Hits) >= core.BattleshipTilesToHitCount {
+		return NewGameOverError("own_field", "all own ships are already sunk")
+	}
+
+	if len(r.OtherField.Hits) >= core.BattleshipTilesToHitCount {
+		return NewGameOverError("other_field", "all opponent ships are already sunk")
+	}
+
+	if len(r.OtherField.Hits)+len(r.OtherField.Misses) >= core.BattleshipFieldSize*core.BattleshipFieldSize {
+		return NewNoPositionsLeftError()
+	}
+
+	return nil
+}
+
+type Violations struct {
+	violations []*errdetails.BadRequest_FieldViolation
+}
+
+func NewViolations() Violations {
+	return Violations{make([]*errdetails.BadRequest_FieldViolation, 0)}
+}
+
+func (v *Violations) Add(field, description string) {
+	v.violations = append(v.violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// AddPositions reports positions that are out of bounds or struck more than once.
+func (v *Violations) AddPositions(field string, hits, misses []*pbcore.BattleshipPosProto) {
+	seen := make(map[core.BattleshipPos]string, len(hits)+len(misses))
+
+	check := func(name string, ps []*pbcore.BattleshipPosProto) {
+		for i, p := range ps {
+			path := fmt.Sprintf("%v.%v[%v]", field, name, i)
+
+			if p == nil {
+				v.Add(path, "must be present")
+				continue
+			}
+
+			pos := core.NewBattleshipPosFromProto(p)
+
+			if !pos.IsInBounds() {
+				v.Add(path, fmt.Sprintf("%v is out of bounds", pos))
+				continue
+			}
+
+			if prev, ok := seen[pos]; ok {
+				v.Add(path, fmt.Sprintf("%v was already struck at %v", pos, prev))
+				continue
+			}
+
+			seen[pos] = path
+		}
+	}
+
+	check("hits", hits)
+	check("misses", misses)
+}
+
+func (v *Violations) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	descriptions := make([]string, 0, len(v.violations))
+	for _, fv := range v.violations {
+		descriptions = append(descriptions, fmt.Sprintf("%v: %v", fv.Field, fv.Description))
+	}
+
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid request: %v", strings.Join(descriptions, "; ")))
+
+	return withDetails(
+		st,
+		&errdetails.ErrorInfo{Reason: ErrorReasonInvalidRequest, Domain: ErrorInfoDomain},
+		&errdetails.BadRequest{FieldViolations: v.violations},
+	)
+}
+
+func NewGameOverError(subject, description string) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("game is over: %v", description))
+
+	return withDetails(
+		st,
+		&errdetails.ErrorInfo{Reason: ErrorReasonGameOver, Domain: ErrorInfoDomain},
+		&errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+			{Type: ErrorReasonGameOver, Subject: subject, Description: description},
+		}},
+	)
+}
+
+func NewNoPositionsLeftError() error {
+	st := status.New(codes.ResourceExhausted, "nowhere left to strike")
+
+	return withDetails(st, &errdetails.ErrorInfo{Reason: ErrorReasonNoPositionsLeft, Domain: ErrorInfoDomain})
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
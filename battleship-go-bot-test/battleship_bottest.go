@@ -0,0 +1,208 @@
+// Package bottest plays full games against a bot served over an in-memory
+// gRPC connection and checks the bot follows the protocol: fields are valid,
+// shots are in range and never repeated, and responses come within the
+// deadline. A bot built with the bot SDK is tested with a single line:
+//
+//	func TestBot(t *testing.T) {
+//		bottest.Run(t, MyPlacer{}, MyShooter{})
+//	}
+package bottest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	botsdk "github.com/mtratsiuk/battleship/battleship-go-bot-sdk"
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+)
+
+const (
+	BotPlayerId      = "bot"
+	OpponentPlayerId = "opponent"
+)
+
+// Options of the games played against the bot, see NewOptions for defaults.
+type Options struct {
+	Games    int
+	Opponent Opponent
+	// Timeout is the deadline of every bot request.
+	Timeout time.Duration
+	// Secret requests are signed with, unless it is empty.
+	Secret string
+}
+
+func NewOptions() Options {
+	o := Options{}
+	o.Games = 10
+	o.Opponent = RandomOpponent(1)
+	o.Timeout = time.Second
+
+	return o
+}
+
+type Option func(o *Options)
+
+func WithGames(games int) Option {
+	return func(o *Options) {
+		o.Games = games
+	}
+}
+
+func WithOpponent(opponent Opponent) Option {
+	return func(o *Options) {
+		o.Opponent = opponent
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// GameResult is the outcome of a game against the bot.
+type GameResult struct {
+	Game *core.BattleshipGame
+	Won  bool
+	// Shots is the number of the bot's shots.
+	Shots      int
+	Violations []Violation
+	// Err is set if the game failed for a reason other than a violation,
+	// e.g. the opponent failed or the game was too long.
+	Err error
+}
+
+type Report struct {
+	Games []GameResult
+}
+
+func (r Report) Wins() int {
+	wins := 0
+
+	for _, g := range r.Games {
+		if g.Won {
+			wins += 1
+		}
+	}
+
+	return wins
+}
+
+// Failures returns violations and errors of all games.
+func (r Report) Failures() []string {
+	failures := make([]string, 0)
+
+	for _, g := range r.Games {
+		for _, v := range g.Violations {
+			failures = append(failures, v.String())
+		}
+
+		if g.Err != nil {
+			failures = append(failures, fmt.Sprintf("game %v: %v", g.Game.Id, g.Err))
+		}
+	}
+
+	return failures
+}
+
+// PlayGames plays games against the bot served by the client, the bot
+// strikes first in even games.
+func PlayGames(ctx context.Context, client pbbot.BattleshipBotServiceClient, options Options) Report {
+	report := Report{}
+	report.Games = make([]GameResult, 0, options.Games)
+
+	for i := 0; i < options.Games; i += 1 {
+		if ctx.Err() != nil {
+			break
+		}
+
+		bot := newCheckedPlayerDriver(core.NewBotPlayerDriver(client, options.Secret, options.Timeout), options.Timeout)
+
+		players := map[string]core.PlayerDriver{
+			BotPlayerId:      bot,
+			OpponentPlayerId: options.Opponent(i),
+		}
+
+		g := core.NewBattleshipGame(core.NewId(), BotPlayerId, OpponentPlayerId)
+		if i%2 == 1 {
+			g = core.NewBattleshipGame(core.NewId(), OpponentPlayerId, BotPlayerId)
+		}
+
+		err := core.PlayBattleshipGame(ctx, g, players)
+		winnerId, _ := g.WinnerId()
+
+		result := GameResult{}
+		result.Game = g
+		result.Won = winnerId == BotPlayerId
+		result.Shots = bot.shots
+		result.Violations = bot.violations
+
+		if err != nil && !errors.Is(err, errViolation) {
+			result.Err = err
+		}
+
+		report.Games = append(report.Games, result)
+	}
+
+	return report
+}
+
+// Run plays games against a bot SDK bot with the placer and shooter, failing
+// the test on every violation. Requests are signed, so the bot's
+// interceptors are exercised as well.
+func Run(t testing.TB, placer botsdk.Placer, shooter botsdk.Shooter, opts ...Option) Report {
+	t.Helper()
+
+	config := botsdk.NewConfig()
+	config.Name = t.Name()
+	config.LogLevel = "error"
+	config.Secret = core.NewId()
+
+	bot, err := botsdk.NewBot(config, placer, shooter)
+	if err != nil {
+		t.Fatalf("failed to create bot: %v", err)
+	}
+
+	h, err := NewHarness(bot.NewGrpcServer())
+	if err != nil {
+		t.Fatalf("failed to start bot: %v", err)
+	}
+	defer h.Close()
+
+	return run(t, h.Client, config.Secret, opts)
+}
+
+// RunService is Run for bots implementing BattleshipBotService directly.
+func RunService(t testing.TB, service pbbot.BattleshipBotServiceServer, opts ...Option) Report {
+	t.Helper()
+
+	h, err := NewServiceHarness(service)
+	if err != nil {
+		t.Fatalf("failed to start bot: %v", err)
+	}
+	defer h.Close()
+
+	return run(t, h.Client, "", opts)
+}
+
+func run(t testing.TB, client pbbot.BattleshipBotServiceClient, secret string, opts []Option) Report {
+	t.Helper()
+
+	options := NewOptions()
+	options.Secret = secret
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	report := PlayGames(context.Background(), client, options)
+
+	for _, f := range report.Failures() {
+		t.Error(f)
+	}
+
+	return report
+}
@@ -0,0 +1,110 @@
+package bottest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errViolation ends a game because the bot broke an invariant, the
+// violation itself is recorded by checkedPlayerDriver.
+var errViolation = errors.New("bot broke a protocol invariant")
+
+// Violation is a protocol invariant broken by the bot.
+type Violation struct {
+	GameId string
+	// Shot is the number of the bot's shot, starting from 1, or 0 for the field.
+	Shot    int
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Shot == 0 {
+		return fmt.Sprintf("game %v, field: %v", v.GameId, v.Message)
+	}
+
+	return fmt.Sprintf("game %v, shot %v: %v", v.GameId, v.Shot, v.Message)
+}
+
+// checkedPlayerDriver records violations of the bot driven by the wrapped
+// driver: failed requests, responses slower than the timeout, invalid fields,
+// out of range and repeated shots.
+type checkedPlayerDriver struct {
+	core.PlayerDriver
+
+	timeout    time.Duration
+	shots      int
+	violations []Violation
+}
+
+func newCheckedPlayerDriver(driver core.PlayerDriver, timeout time.Duration) *checkedPlayerDriver {
+	d := &checkedPlayerDriver{}
+	d.PlayerDriver = driver
+	d.timeout = timeout
+	d.violations = make([]Violation, 0)
+
+	return d
+}
+
+func (d *checkedPlayerDriver) violate(gameId string, message string) error {
+	d.violations = append(d.violations, Violation{GameId: gameId, Shot: d.shots, Message: message})
+
+	return errViolation
+}
+
+func (d *checkedPlayerDriver) checkResponse(gameId, method string, elapsed time.Duration, err error) error {
+	if status.Code(err) == codes.DeadlineExceeded {
+		return d.violate(gameId, fmt.Sprintf("%v didn't respond within %v", method, d.timeout))
+	}
+
+	if err != nil {
+		return d.violate(gameId, fmt.Sprintf("%v failed: %v", method, err))
+	}
+
+	if d.timeout > 0 && elapsed > d.timeout {
+		return d.violate(gameId, fmt.Sprintf("%v took %v, deadline is %v", method, elapsed, d.timeout))
+	}
+
+	return nil
+}
+
+func (d *checkedPlayerDriver) Field(ctx context.Context, gameId string) (core.BattleshipField, error) {
+	start := time.Now()
+	f, err := d.PlayerDriver.Field(ctx, gameId)
+
+	if err := d.checkResponse(gameId, "GetField", time.Since(start), err); err != nil {
+		return f, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return f, d.violate(gameId, fmt.Sprintf("invalid field: %v", err))
+	}
+
+	return f, nil
+}
+
+func (d *checkedPlayerDriver) Strike(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	d.shots += 1
+
+	start := time.Now()
+	pos, err := d.PlayerDriver.Strike(ctx, gameId, own, other)
+
+	if err := d.checkResponse(gameId, "GetStrike", time.Since(start), err); err != nil {
+		return pos, err
+	}
+
+	if !pos.IsInBounds() {
+		return pos, d.violate(gameId, fmt.Sprintf("position %v is out of range", pos))
+	}
+
+	if other.Hits.Has(pos) || other.Misses.Has(pos) {
+		return pos, d.violate(gameId, fmt.Sprintf("position %v was already struck", pos))
+	}
+
+	return pos, nil
+}
@@ -0,0 +1,62 @@
+package bottest
+
+import (
+	"context"
+	"net"
+
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+// Harness serves a gRPC server over an in-memory listener and connects to it,
+// so bots are exercised through the real gRPC stack without opening ports.
+type Harness struct {
+	listener *bufconn.Listener
+	server   *grpc.Server
+	conn     *grpc.ClientConn
+	Client   pbbot.BattleshipBotServiceClient
+}
+
+// NewHarness starts serving the server, which must have BattleshipBotService
+// registered. Close stops the server.
+func NewHarness(server *grpc.Server) (*Harness, error) {
+	h := &Harness{}
+	h.listener = bufconn.Listen(bufSize)
+	h.server = server
+
+	go server.Serve(h.listener)
+
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		return h.listener.DialContext(ctx)
+	}
+
+	conn, err := grpc.Dial("bufconn", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+
+	h.conn = conn
+	h.Client = pbbot.NewBattleshipBotServiceClient(conn)
+
+	return h, nil
+}
+
+// NewServiceHarness serves the service without any interceptors.
+func NewServiceHarness(service pbbot.BattleshipBotServiceServer) (*Harness, error) {
+	server := grpc.NewServer()
+	pbbot.RegisterBattleshipBotServiceServer(server, service)
+
+	return NewHarness(server)
+}
+
+func (h *Harness) Close() error {
+	err := h.conn.Close()
+	h.server.Stop()
+
+	return err
+}
@@ -0,0 +1,74 @@
+package bottest
+
+import (
+	"context"
+	"math/rand"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	strategy "github.com/mtratsiuk/battleship/battleship-go-strategy"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+)
+
+// Opponent creates the player the bot plays the given game against, games
+// are numbered from 0.
+type Opponent func(game int) core.PlayerDriver
+
+// RandomOpponent places the fleet and strikes at random, never striking the
+// same position twice. Games are reproducible for the same seed.
+func RandomOpponent(seed int64) Opponent {
+	return func(game int) core.PlayerDriver {
+		return core.NewStrategyPlayerDriver(strategy.RandomPlacement{}, strategy.RandomTargeting{}, rand.New(rand.NewSource(seed+int64(game))))
+	}
+}
+
+// ScriptedOpponent plays the same field and shots every game. Once the shots
+// run out, it strikes the first position that wasn't struck yet.
+func ScriptedOpponent(field core.BattleshipField, shots []core.BattleshipPos) Opponent {
+	return func(game int) core.PlayerDriver {
+		return NewScriptedPlayerDriver(field, shots)
+	}
+}
+
+// ScriptedPlayerDriver returns a fixed field and fixed shots.
+type ScriptedPlayerDriver struct {
+	field core.BattleshipField
+	shots []core.BattleshipPos
+	next  int
+}
+
+func NewScriptedPlayerDriver(field core.BattleshipField, shots []core.BattleshipPos) *ScriptedPlayerDriver {
+	d := &ScriptedPlayerDriver{}
+	d.field = field
+	d.shots = shots
+
+	return d
+}
+
+func (d *ScriptedPlayerDriver) Info(ctx context.Context) (*pbcore.BotInfoProto, error) {
+	return nil, nil
+}
+
+func (d *ScriptedPlayerDriver) Field(ctx context.Context, gameId string) (core.BattleshipField, error) {
+	return d.field, nil
+}
+
+func (d *ScriptedPlayerDriver) Strike(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	if d.next < len(d.shots) {
+		pos := d.shots[d.next]
+		d.next += 1
+
+		return pos, nil
+	}
+
+	view := core.NewBattleshipOpponentViewFromField(other)
+
+	unknown := view.Unknown()
+	if len(unknown) == 0 {
+		return core.BattleshipPos{}, core.ErrNoPositionsLeft
+	}
+
+	return unknown[0], nil
+}
+
+func (d *ScriptedPlayerDriver) GameOver(ctx context.Context, gameId string, won bool, opponent *core.BattleshipField) {
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// Server signs requests to bots with the secret the bot joined the lobby
+// with, so bots can reject requests that don't come from the server.
+const (
+	SignatureMetadataKey          = "x-battleship-signature"
+	SignatureTimestampMetadataKey = "x-battleship-timestamp"
+)
+
+// GameIdMetadataKey carries the game id of streaming requests, which is
+// signed before the first message is sent.
+const GameIdMetadataKey = "x-battleship-game-id"
+
+// SignBotRequest returns hex encoded HMAC-SHA256 of the method (without the
+// leading slash), game id, unix milliseconds timestamp and hex encoded
+// SHA-256 of the body, separated by newlines. The body is the serialized
+// request message, empty for streaming requests, which are signed before
+// the first message is sent.
+func SignBotRequest(secret, method, gameId string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%v\n%v\n%v\n%x", strings.TrimPrefix(method, "/"), gameId, timestamp, sha256.Sum256(body))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// marshalBotRequest returns the signed body of the request, nil for
+// streaming requests. It's serialized like the gRPC codec does before
+// sending it, bots verify the body exactly as it was received.
+func marshalBotRequest(request proto.Message) ([]byte, error) {
+	if request == nil {
+		return nil, nil
+	}
+
+	return proto.Marshal(request)
+}
+
+// NewSignatureMetadata returns metadata signing a bot request made at now,
+// request is nil for streaming requests.
+func NewSignatureMetadata(secret, method, gameId string, request proto.Message, now time.Time) (metadata.MD, error) {
+	body, err := marshalBotRequest(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize request to sign: %w", err)
+	}
+
+	timestamp := now.UnixMilli()
+
+	md := metadata.Pairs(
+		SignatureTimestampMetadataKey, strconv.FormatInt(timestamp, 10),
+		SignatureMetadataKey, SignBotRequest(secret, method, gameId, timestamp, body),
+	)
+
+	return md, nil
+}
+
+// VerifyBotRequestSignature checks the signature of a bot request and that
+// it was made within maxSkew of now. Body is the serialized request as it
+// was received, rather than the parsed request serialized again, which would
+// drop fields unknown to the bot. It's empty for streaming requests.
+func VerifyBotRequestSignature(secret, method, gameId string, body []byte, timestamp, signature string, now time.Time, maxSkew time.Duration) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid signature timestamp %q", timestamp)
+	}
+
+	if skew := now.Sub(time.UnixMilli(ts)).Abs(); skew > maxSkew {
+		return fmt.Errorf("signature timestamp is %v off, at most %v is allowed", skew, maxSkew)
+	}
+
+	expected := SignBotRequest(secret, method, gameId, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return errors.New("invalid signature")
+	}
+
+	return nil
+}
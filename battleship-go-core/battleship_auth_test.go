@@ -0,0 +1,57 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVerifyBotRequestSignature(t *testing.T) {
+	const secret = "0123456789abcdef"
+
+	method := pbbot.BattleshipBotService_GetField_FullMethodName
+	now := time.UnixMilli(1700000000000)
+	request := &pbbot.GetFieldRequest{GameId: "game", OpponentName: "opponent"}
+
+	md, err := NewSignatureMetadata(secret, method, "game", request, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp, signature := md.Get(SignatureTimestampMetadataKey)[0], md.Get(SignatureMetadataKey)[0]
+
+	body, _ := proto.Marshal(request)
+	other, _ := proto.Marshal(&pbbot.GetFieldRequest{GameId: "game", OpponentName: "someone else"})
+
+	tests := []struct {
+		name   string
+		secret string
+		gameId string
+		body   []byte
+		now    time.Time
+		valid  bool
+	}{
+		{"signed request", secret, "game", body, now, true},
+		{"other secret", "fedcba9876543210", "game", body, now, false},
+		{"other game", secret, "other", body, now, false},
+		{"other body", secret, "game", other, now, false},
+		{"streaming request", secret, "game", nil, now, false},
+		{"expired", secret, "game", body, now.Add(2 * time.Minute), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := VerifyBotRequestSignature(test.secret, method, test.gameId, test.body, timestamp, signature, test.now, time.Minute)
+
+			if test.valid && err != nil {
+				t.Fatalf("expected a valid signature, got %v", err)
+			}
+
+			if !test.valid && err == nil {
+				t.Fatal("expected an invalid signature")
+			}
+		})
+	}
+}
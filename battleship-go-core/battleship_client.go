@@ -0,0 +1,316 @@
+package core
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// AuthMetadataKey is the metadata key carrying the auth token of server client requests.
+const AuthMetadataKey = "authorization"
+
+// ServerClientOption configures NewBattleshipServerServiceClient.
+type ServerClientOption func(*serverClientOptions)
+
+type serverClientOptions struct {
+	tls               *tls.Config
+	authToken         string
+	dialTimeout       time.Duration
+	keepalive         *keepalive.ClientParameters
+	retryMaxAttempts  int
+	unaryInterceptors []grpc.UnaryClientInterceptor
+	dialOptions       []grpc.DialOption
+}
+
+// WithTLS enables TLS with the given config, see NewClientTLSConfig.
+func WithTLS(config *tls.Config) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.tls = config
+	}
+}
+
+// WithAuthToken sends the token as a bearer token with every request.
+// The token is only sent over TLS.
+func WithAuthToken(token string) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.authToken = token
+	}
+}
+
+// WithDialTimeout makes the client wait until the connection is established,
+// failing if it takes longer than timeout.
+func WithDialTimeout(timeout time.Duration) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithKeepalive pings the server after interval of inactivity, closing the
+// connection if a ping isn't acknowledged within timeout.
+func WithKeepalive(interval, timeout time.Duration) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.keepalive = &keepalive.ClientParameters{Time: interval, Timeout: timeout, PermitWithoutStream: true}
+	}
+}
+
+// WithRetry retries idempotent requests (GetGames, GetGame) failing with
+// UNAVAILABLE, making at most maxAttempts attempts (gRPC caps it at 5).
+func WithRetry(maxAttempts int) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.retryMaxAttempts = maxAttempts
+	}
+}
+
+func WithUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	}
+}
+
+func WithDialOptions(opts ...grpc.DialOption) ServerClientOption {
+	return func(o *serverClientOptions) {
+		o.dialOptions = append(o.dialOptions, opts...)
+	}
+}
+
+// NewBattleshipServerServiceClient connects to the server at addr. Without
+// options the connection is insecure and established lazily.
+func NewBattleshipServerServiceClient(addr string, opts ...ServerClientOption) (pbserver.BattleshipServerServiceClient, func(), error) {
+	o := serverClientOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dialOpts := make([]grpc.DialOption, 0)
+
+	if o.tls != nil {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(o.tls)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if o.authToken != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerToken(o.authToken)))
+	}
+
+	if o.keepalive != nil {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(*o.keepalive))
+	}
+
+	if o.retryMaxAttempts > 1 {
+		dialOpts = append(dialOpts, grpc.WithDefaultServiceConfig(retryServiceConfig(o.retryMaxAttempts)))
+	}
+
+	if len(o.unaryInterceptors) > 0 {
+		dialOpts = append(dialOpts, grpc.WithChainUnaryInterceptor(o.unaryInterceptors...))
+	}
+
+	dialOpts = append(dialOpts, o.dialOptions...)
+
+	ctx := context.Background()
+
+	if o.dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.dialTimeout)
+		defer cancel()
+
+		dialOpts = append(dialOpts, grpc.WithBlock(), grpc.WithReturnConnectionError())
+	}
+
+	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %v: %w", addr, err)
+	}
+
+	return pbserver.NewBattleshipServerServiceClient(conn), func() { conn.Close() }, nil
+}
+
+func retryServiceConfig(maxAttempts int) string {
+	service := pbserver.BattleshipServerService_ServiceDesc.ServiceName
+
+	return fmt.Sprintf(`{
+	"methodConfig": [{
+		"name": [{"service": %q, "method": "GetGames"}, {"service": %q, "method": "GetGame"}],
+		"retryPolicy": {
+			"maxAttempts": %d,
+			"initialBackoff": "0.1s",
+			"maxBackoff": "1s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`, service, service, maxAttempts)
+}
+
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{AuthMetadataKey: "Bearer " + string(t)}, nil
+}
+
+func (t bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// NewClientTLSConfig creates a client TLS config. Server certificate is
+// verified against the CA certificates of caFile, or the system pool if
+// caFile is empty. Client certificate is presented if certFile and keyFile are set.
+func NewClientTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pool, err := LoadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// LoadCertPool reads PEM encoded certificates from path.
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificates: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %v", path)
+	}
+
+	return pool, nil
+}
+
+// ServerClientConfig configures the connection to BattleshipServerService.
+type ServerClientConfig struct {
+	ServerHost        string        `yaml:"server_host" toml:"server_host" env:"BATTLESHIP_SERVER_GRPC_HOST" flag:"server-host" usage:"battleship server gRPC host"`
+	ServerPort        string        `yaml:"server_port" toml:"server_port" env:"BATTLESHIP_SERVER_GRPC_PORT" flag:"server-port" usage:"battleship server gRPC port"`
+	ServerTLS         bool          `yaml:"server_tls" toml:"server_tls" env:"BATTLESHIP_SERVER_TLS" flag:"server-tls" usage:"connect to the battleship server over TLS"`
+	ServerCAFile      string        `yaml:"server_ca_file" toml:"server_ca_file" env:"BATTLESHIP_SERVER_CA_FILE" flag:"server-ca-file" usage:"CA certificates to verify the battleship server with, system ones if empty"`
+	ServerCertFile    string        `yaml:"server_cert_file" toml:"server_cert_file" env:"BATTLESHIP_SERVER_CERT_FILE" flag:"server-cert-file" usage:"client certificate presented to the battleship server"`
+	ServerKeyFile     string        `yaml:"server_key_file" toml:"server_key_file" env:"BATTLESHIP_SERVER_KEY_FILE" flag:"server-key-file" usage:"client certificate key"`
+	ServerAuthToken   string        `yaml:"server_auth_token" toml:"server_auth_token" env:"BATTLESHIP_SERVER_AUTH_TOKEN" flag:"server-auth-token" usage:"bearer token sent to the battleship server" secret:"true"`
+	ServerDialTimeout time.Duration `yaml:"server_dial_timeout" toml:"server_dial_timeout" env:"BATTLESHIP_SERVER_DIAL_TIMEOUT" flag:"server-dial-timeout" usage:"wait for the battleship server connection up to this timeout, 0 to connect lazily"`
+	ServerKeepalive   time.Duration `yaml:"server_keepalive" toml:"server_keepalive" env:"BATTLESHIP_SERVER_KEEPALIVE" flag:"server-keepalive" usage:"ping the battleship server after this period of inactivity, 0 to disable"`
+	ServerRetries     int           `yaml:"server_retries" toml:"server_retries" env:"BATTLESHIP_SERVER_RETRIES" flag:"server-retries" usage:"maximum attempts of idempotent battleship server requests"`
+}
+
+func NewServerClientConfig() ServerClientConfig {
+	c := ServerClientConfig{}
+	c.ServerHost = "localhost"
+	c.ServerPort = "6969"
+	c.ServerRetries = 3
+
+	return c
+}
+
+func (c ServerClientConfig) Addr() string {
+	return fmt.Sprintf("%v:%v", c.ServerHost, c.ServerPort)
+}
+
+func (c ServerClientConfig) Validate() error {
+	errs := make([]error, 0)
+
+	if c.ServerHost == "" {
+		errs = append(errs, errors.New("server_host must not be empty"))
+	}
+
+	if err := ValidatePort(c.ServerPort); err != nil {
+		errs = append(errs, fmt.Errorf("server_port: %w", err))
+	}
+
+	if !c.ServerTLS && (c.ServerCAFile != "" || c.ServerCertFile != "" || c.ServerKeyFile != "") {
+		errs = append(errs, errors.New("server_ca_file, server_cert_file and server_key_file require server_tls"))
+	}
+
+	if (c.ServerCertFile == "") != (c.ServerKeyFile == "") {
+		errs = append(errs, errors.New("server_cert_file and server_key_file must be set together"))
+	}
+
+	if c.ServerTLS {
+		if _, err := NewClientTLSConfig(c.ServerCAFile, c.ServerCertFile, c.ServerKeyFile); err != nil {
+			errs = append(errs, fmt.Errorf("server_tls: %w", err))
+		}
+	}
+
+	if !c.ServerTLS && c.ServerAuthToken != "" {
+		errs = append(errs, errors.New("server_auth_token requires server_tls"))
+	}
+
+	if c.ServerDialTimeout < 0 {
+		errs = append(errs, errors.New("server_dial_timeout must not be negative"))
+	}
+
+	if c.ServerKeepalive != 0 && c.ServerKeepalive < 10*time.Second {
+		errs = append(errs, errors.New("server_keepalive must be at least 10s"))
+	}
+
+	if c.ServerRetries < 1 || c.ServerRetries > 5 {
+		errs = append(errs, errors.New("server_retries must be between 1 and 5"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Options converts the config to client options, loading TLS certificates.
+func (c ServerClientConfig) Options() ([]ServerClientOption, error) {
+	opts := make([]ServerClientOption, 0)
+
+	if c.ServerTLS {
+		config, err := NewClientTLSConfig(c.ServerCAFile, c.ServerCertFile, c.ServerKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, WithTLS(config))
+	}
+
+	if c.ServerAuthToken != "" {
+		opts = append(opts, WithAuthToken(c.ServerAuthToken))
+	}
+
+	if c.ServerDialTimeout > 0 {
+		opts = append(opts, WithDialTimeout(c.ServerDialTimeout))
+	}
+
+	if c.ServerKeepalive > 0 {
+		opts = append(opts, WithKeepalive(c.ServerKeepalive, 20*time.Second))
+	}
+
+	opts = append(opts, WithRetry(c.ServerRetries))
+
+	return opts, nil
+}
+
+// NewClient connects to the configured server, see NewBattleshipServerServiceClient.
+func (c ServerClientConfig) NewClient(opts ...ServerClientOption) (pbserver.BattleshipServerServiceClient, func(), error) {
+	configOpts, err := c.Options()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return NewBattleshipServerServiceClient(c.Addr(), append(configOpts, opts...)...)
+}
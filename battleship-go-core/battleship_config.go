@@ -0,0 +1,331 @@
+package core
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrConfigPrinted is returned by LoadConfig when --print-config was passed
+// and the config was printed instead of being used.
+var ErrConfigPrinted = errors.New("config printed")
+
+// LoadConfig fills cfg, a pointer to a struct holding default values, from
+// the following layers, each overriding the previous one:
+//
+//   - YAML (.yaml, .yml) or TOML (.toml) config file passed via --config
+//     or the configEnv variable; keys are taken from yaml and toml tags
+//   - environment variables named by env tags
+//   - command line flags named by flag tags, described by usage tags
+//
+// Environment variables and flags of string slices are comma separated.
+//
+// Fields tagged secret:"true" are masked by --print-config.
+// Embedded structs are flattened, so configs can be composed. Errors of the
+// environment and flag layers are reported at once, together with the error
+// of the config Validate method, if it has one.
+func LoadConfig(cfg any, name string, args []string, configEnv string, out io.Writer) error {
+	fields := configFields(reflect.ValueOf(cfg).Elem())
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(out)
+
+	path := fs.String("config", "", fmt.Sprintf("path to a YAML or TOML config file (env %v)", configEnv))
+	print := fs.Bool("print-config", false, "print the resulting config and exit")
+
+	flags := make(map[string]*configFlag)
+
+	for _, f := range fields {
+		if f.flag == "" {
+			continue
+		}
+
+		usage := f.usage
+		if f.env != "" {
+			usage = fmt.Sprintf("%v (env %v)", usage, f.env)
+		}
+
+		flags[f.flag] = &configFlag{field: f}
+		fs.Var(flags[f.flag], f.flag, usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", strings.Join(fs.Args(), " "))
+	}
+
+	if *path == "" {
+		*path = EnvOr(configEnv, "")
+	}
+
+	if *path != "" {
+		if err := loadConfigFile(cfg, *path); err != nil {
+			return err
+		}
+	}
+
+	errs := make([]error, 0)
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+
+		if raw, ok := os.LookupEnv(f.env); ok {
+			if err := setConfigField(f.value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("env %v: %w", f.env, err))
+			}
+		}
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		if cf, ok := flags[fl.Name]; ok {
+			if err := setConfigField(cf.field.value, cf.raw); err != nil {
+				errs = append(errs, fmt.Errorf("flag --%v: %w", fl.Name, err))
+			}
+		}
+	})
+
+	if v, ok := cfg.(interface{ Validate() error }); ok {
+		errs = append(errs, v.Validate())
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if *print {
+		if err := yaml.NewEncoder(out).Encode(maskSecrets(cfg)); err != nil {
+			return err
+		}
+
+		return ErrConfigPrinted
+	}
+
+	return nil
+}
+
+// MustLoadConfig loads cfg from the process arguments, exiting the process
+// with all config errors printed, or after printing the config on --print-config.
+func MustLoadConfig(cfg any, configEnv string) {
+	err := LoadConfig(cfg, filepath.Base(os.Args[0]), os.Args[1:], configEnv, os.Stdout)
+
+	switch {
+	case err == nil:
+		return
+	case errors.Is(err, ErrConfigPrinted), errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	default:
+		fmt.Fprintln(os.Stderr, "invalid config:")
+
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "  %v\n", line)
+		}
+
+		os.Exit(2)
+	}
+}
+
+type configField struct {
+	value reflect.Value
+	env   string
+	flag  string
+	usage string
+}
+
+func configFields(v reflect.Value) []configField {
+	fields := make([]configField, 0)
+
+	for i := 0; i < v.NumField(); i += 1 {
+		sf := v.Type().Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, configFields(v.Field(i))...)
+			continue
+		}
+
+		f := configField{}
+		f.value = v.Field(i)
+		f.env = sf.Tag.Get("env")
+		f.flag = sf.Tag.Get("flag")
+		f.usage = sf.Tag.Get("usage")
+
+		fields = append(fields, f)
+	}
+
+	return fields
+}
+
+// maskSecrets returns a copy of cfg with non-empty secret string fields masked.
+func maskSecrets(cfg any) any {
+	masked := reflect.New(reflect.TypeOf(cfg).Elem())
+	masked.Elem().Set(reflect.ValueOf(cfg).Elem())
+	maskSecretFields(masked.Elem())
+
+	return masked.Interface()
+}
+
+func maskSecretFields(v reflect.Value) {
+	for i := 0; i < v.NumField(); i += 1 {
+		sf := v.Type().Field(i)
+		f := v.Field(i)
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		switch {
+		case sf.Tag.Get("secret") == "true" && f.Kind() == reflect.String && f.String() != "":
+			f.SetString("******")
+		case f.Kind() == reflect.Struct:
+			maskSecretFields(f)
+		case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.Struct:
+			// copy elements, so the original slice isn't masked
+			items := reflect.MakeSlice(f.Type(), f.Len(), f.Len())
+			reflect.Copy(items, f)
+
+			for j := 0; j < items.Len(); j += 1 {
+				maskSecretFields(items.Index(j))
+			}
+
+			f.Set(items)
+		}
+	}
+}
+
+func loadConfigFile(cfg any, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(f)
+		decoder.KnownFields(true)
+
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse config file %v: %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.NewDecoder(f).Decode(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to parse config file %v: %w", path, err)
+		}
+
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse config file %v: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .toml", ext)
+	}
+
+	return nil
+}
+
+func setConfigField(v reflect.Value, raw string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config value type %v", v.Type())
+		}
+
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		v.Set(reflect.ValueOf(items))
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", raw)
+		}
+
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", raw)
+		}
+
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("expected a boolean, got %q", raw)
+		}
+
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported config value type %v", v.Type())
+	}
+
+	return nil
+}
+
+// configFlag records the raw flag value, it is applied after the config
+// file and environment variables.
+type configFlag struct {
+	field configField
+	raw   string
+}
+
+func (f *configFlag) String() string {
+	if f == nil || !f.field.value.IsValid() {
+		return ""
+	}
+
+	return fmt.Sprint(f.field.value.Interface())
+}
+
+func (f *configFlag) Set(raw string) error {
+	f.raw = raw
+	return setConfigField(reflect.New(f.field.value.Type()).Elem(), raw)
+}
+
+func (f *configFlag) IsBoolFlag() bool {
+	return f.field.value.Kind() == reflect.Bool
+}
+
+// ValidatePort checks that port is a number between 1 and 65535.
+func ValidatePort(port string) error {
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("expected a port number between 1 and 65535, got %q", port)
+	}
+
+	return nil
+}
@@ -1,20 +1,33 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"slices"
 	"strings"
 
 	"github.com/mtratsiuk/adventofcode/gotils"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
 	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
-	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 const BattleshipFieldSize = 10
 
+// BattleshipProtocolVersion is the version of the bot protocol reported by GetInfo.
+const BattleshipProtocolVersion = 1
+
+// BattleshipCapabilityPlayGameStream is reported by GetInfo of bots implementing PlayGame.
+const BattleshipCapabilityPlayGameStream = "play_game_stream"
+
+// Ruleset and variant played by default and assumed for bots not implementing GetInfo.
+const (
+	BattleshipDefaultRuleset = "classic"
+	BattleshipDefaultVariant = "standard"
+)
+
+var ErrNoPositionsLeft = errors.New("nowhere left to strike")
+
 type BattleshipKind rune
 
 const (
@@ -41,6 +54,17 @@ var BattleshipKindSizes = map[BattleshipKind]int{
 	BattleshipKindCarrier:    5,
 }
 
+// BattleshipTilesToHitCount is the number of hits required to sink the whole fleet.
+var BattleshipTilesToHitCount = func() int {
+	count := 0
+
+	for _, size := range BattleshipKindSizes {
+		count += size
+	}
+
+	return count
+}()
+
 func (b BattleshipKind) IsBattleshipKind() bool {
 	return slices.Contains(BattleshipKinds, b)
 }
@@ -77,6 +101,14 @@ type BattleshipPos struct {
 	X, Y int
 }
 
+func (p BattleshipPos) IsInBounds() bool {
+	return p.X >= 0 && p.X < BattleshipFieldSize && p.Y >= 0 && p.Y < BattleshipFieldSize
+}
+
+func (p BattleshipPos) String() string {
+	return fmt.Sprintf("Pos[%v,%v]", p.X, p.Y)
+}
+
 func NewBattleshipPosFromProto(p *pbcore.BattleshipPosProto) BattleshipPos {
 	pos := BattleshipPos{}
 
@@ -86,6 +118,10 @@ func NewBattleshipPosFromProto(p *pbcore.BattleshipPosProto) BattleshipPos {
 	return pos
 }
 
+func (p BattleshipPos) ToProto() *pbcore.BattleshipPosProto {
+	return &pbcore.BattleshipPosProto{X: int32(p.X), Y: int32(p.Y)}
+}
+
 type BattleshipField struct {
 	Field  [BattleshipFieldSize][BattleshipFieldSize]BattleshipTile
 	Hits   gotils.Set[BattleshipPos]
@@ -120,6 +156,31 @@ func (b *BattleshipField) ToProto() *pbcore.BattleshipFieldProto {
 
 	bp.Field = strings.TrimSpace(sb.String())
 
+	for _, h := range b.Hits.Items() {
+		bp.Hits = append(bp.Hits, h.ToProto())
+	}
+
+	for _, m := range b.Misses.Items() {
+		bp.Misses = append(bp.Misses, m.ToProto())
+	}
+
+	return &bp
+}
+
+// ToOtherFieldProto returns hits and misses of the field, as sent to the attacker.
+func (b *BattleshipField) ToOtherFieldProto() *pbbot.BattleshipOtherFieldProto {
+	bp := pbbot.BattleshipOtherFieldProto{}
+	bp.Hits = make([]*pbcore.BattleshipPosProto, 0)
+	bp.Misses = make([]*pbcore.BattleshipPosProto, 0)
+
+	for _, h := range b.Hits.Items() {
+		bp.Hits = append(bp.Hits, h.ToProto())
+	}
+
+	for _, m := range b.Misses.Items() {
+		bp.Misses = append(bp.Misses, m.ToProto())
+	}
+
 	return &bp
 }
 
@@ -134,7 +195,17 @@ func NewBattleshipFieldFromProto(p *pbcore.BattleshipFieldProto) (BattleshipFiel
 		bf.Misses.Add(NewBattleshipPosFromProto(m))
 	}
 
-	for y, l := range strings.Split(p.Field, "\n") {
+	lines := strings.Split(p.Field, "\n")
+
+	if len(lines) != BattleshipFieldSize {
+		return bf, fmt.Errorf("expected field rows count to be %v, got %v", BattleshipFieldSize, len(lines))
+	}
+
+	for y, l := range lines {
+		if len(l) != BattleshipFieldSize {
+			return bf, fmt.Errorf("expected field columns count to be %v, got %v in row %v", BattleshipFieldSize, len(l), y)
+		}
+
 		for x, c := range l {
 			if c == '.' {
 				bf.Field[y][x] = NewEmptyBattleshipTile()
@@ -153,27 +224,119 @@ func NewBattleshipFieldFromProto(p *pbcore.BattleshipFieldProto) (BattleshipFiel
 	return bf, nil
 }
 
-func (b *BattleshipField) Strike(pos BattleshipPos) {
-	if b.Field[pos.Y][pos.X].Kind == BattleshipTileKindEmpty {
-		b.Misses.Add(pos)
-	} else {
-		b.Hits.Add(pos)
+// ShipPositions returns positions covered by a ship of the given kind
+// starting at pos and going either right or down.
+func ShipPositions(ship BattleshipKind, pos BattleshipPos, horizontal bool) []BattleshipPos {
+	ps := make([]BattleshipPos, 0, ship.Size())
+
+	for d := 0; d < ship.Size(); d += 1 {
+		if horizontal {
+			ps = append(ps, BattleshipPos{X: pos.X + d, Y: pos.Y})
+		} else {
+			ps = append(ps, BattleshipPos{X: pos.X, Y: pos.Y + d})
+		}
 	}
+
+	return ps
 }
 
-func NewBattleshipServerServiceClient() (*pbserver.BattleshipServerServiceClient, func(), error) {
-	grpcHost := EnvOr("BATTLESHIP_SERVER_GRPC_HOST", "localhost")
-	grpcPort := EnvOr("BATTLESHIP_SERVER_GRPC_PORT", "6969")
-	serverGrpcUrl := fmt.Sprintf("%v:%v", grpcHost, grpcPort)
+func (b *BattleshipField) CanPlaceShip(ship BattleshipKind, pos BattleshipPos, horizontal bool) bool {
+	for _, p := range ShipPositions(ship, pos, horizontal) {
+		if !p.IsInBounds() || b.Field[p.Y][p.X].Kind != BattleshipTileKindEmpty {
+			return false
+		}
+	}
 
-	conn, err := grpc.Dial(serverGrpcUrl, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return nil, nil, err
+	return true
+}
+
+// PlaceShip puts a ship on the field if it fits and doesn't overlap other ships.
+func (b *BattleshipField) PlaceShip(ship BattleshipKind, pos BattleshipPos, horizontal bool) bool {
+	if !b.CanPlaceShip(ship, pos, horizontal) {
+		return false
+	}
+
+	for _, p := range ShipPositions(ship, pos, horizontal) {
+		b.Field[p.Y][p.X] = NewBattleshipTile(ship)
+	}
+
+	return true
+}
+
+// Ships returns positions of every ship on the field, ordered top to bottom, left to right.
+func (b *BattleshipField) Ships() map[BattleshipKind][]BattleshipPos {
+	ships := make(map[BattleshipKind][]BattleshipPos, len(BattleshipKinds))
+
+	for y, l := range b.Field {
+		for x, t := range l {
+			if t.Kind == BattleshipTileKindShip {
+				ships[t.Ship] = append(ships[t.Ship], BattleshipPos{X: x, Y: y})
+			}
+		}
 	}
 
-	client := pbserver.NewBattleshipServerServiceClient(conn)
+	return ships
+}
+
+// Validate checks that the field contains exactly one straight ship of every kind.
+func (b *BattleshipField) Validate() error {
+	ships := b.Ships()
 
-	return &client, func() { conn.Close() }, nil
+	for _, ship := range BattleshipKinds {
+		positions, ok := ships[ship]
+
+		if !ok {
+			return fmt.Errorf("expected %c to be present", ship)
+		}
+
+		if len(positions) != ship.Size() {
+			return fmt.Errorf("expected %c to have %v tiles, got %v", ship, ship.Size(), len(positions))
+		}
+
+		horizontal := true
+		vertical := true
+
+		for i, pos := range positions[1:] {
+			prev := positions[i]
+
+			horizontal = horizontal && pos.Y == prev.Y && pos.X == prev.X+1
+			vertical = vertical && pos.X == prev.X && pos.Y == prev.Y+1
+		}
+
+		if !horizontal && !vertical {
+			return fmt.Errorf("expected %c tiles to be sequential and either horizontal or vertical", ship)
+		}
+	}
+
+	return nil
+}
+
+func (b *BattleshipField) HasAliveShips() bool {
+	return len(b.Hits.Items()) < BattleshipTilesToHitCount
+}
+
+// NewBattleshipOtherFieldFromProto creates a field with opponent's hits and
+// misses only, as ships positions are not known to the attacker.
+func NewBattleshipOtherFieldFromProto(p *pbbot.BattleshipOtherFieldProto) BattleshipField {
+	bf := NewBattleshipField()
+
+	for _, h := range p.GetHits() {
+		bf.Hits.Add(NewBattleshipPosFromProto(h))
+	}
+
+	for _, m := range p.GetMisses() {
+		bf.Misses.Add(NewBattleshipPosFromProto(m))
+	}
+
+	return bf
+}
+
+func (b *BattleshipField) Strike(pos BattleshipPos) {
+	if b.Field[pos.Y][pos.X].Kind == BattleshipTileKindEmpty {
+		b.Misses.Add(pos)
+	} else {
+		b.Hits.Add(pos)
+	}
 }
 
 func EnvOr(name, fallback string) string {
@@ -0,0 +1,421 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mtratsiuk/adventofcode/gotils"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// BotPlayerDriver drives a bot over its gRPC API. Requests are signed with
+// the secret the bot joined the lobby with, unless it is empty.
+type BotPlayerDriver struct {
+	client  pbbot.BattleshipBotServiceClient
+	secret  string
+	timeout time.Duration
+	close   func() error
+}
+
+// NewBotPlayerDriver creates a driver making requests with the timeout, no
+// timeout is applied if it is zero.
+func NewBotPlayerDriver(client pbbot.BattleshipBotServiceClient, secret string, timeout time.Duration) *BotPlayerDriver {
+	d := &BotPlayerDriver{}
+	d.client = client
+	d.secret = secret
+	d.timeout = timeout
+	d.close = func() error { return nil }
+
+	return d
+}
+
+// DialBotPlayerDriver connects to the bot at addr, without TLS unless
+// credentials are passed in opts.
+func DialBotPlayerDriver(addr, secret string, timeout time.Duration, opts ...grpc.DialOption) (*BotPlayerDriver, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewBotPlayerDriver(pbbot.NewBattleshipBotServiceClient(conn), secret, timeout)
+	d.close = conn.Close
+
+	return d, nil
+}
+
+func (d *BotPlayerDriver) Close() error {
+	return d.close()
+}
+
+func (d *BotPlayerDriver) context(ctx context.Context, method, gameId string, request proto.Message) (context.Context, context.CancelFunc, error) {
+	if d.secret != "" {
+		md, err := NewSignatureMetadata(d.secret, method, gameId, request, time.Now())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	if d.timeout > 0 {
+		ctx, cancel := context.WithTimeout(ctx, d.timeout)
+		return ctx, cancel, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	return ctx, cancel, nil
+}
+
+func (d *BotPlayerDriver) Info(ctx context.Context) (*pbcore.BotInfoProto, error) {
+	request := &pbbot.GetInfoRequest{}
+
+	ctx, cancel, err := d.context(ctx, pbbot.BattleshipBotService_GetInfo_FullMethodName, "", request)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	resp, err := d.client.GetInfo(ctx, request)
+	if status.Code(err) == codes.Unimplemented {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetInfo(), nil
+}
+
+func (d *BotPlayerDriver) Field(ctx context.Context, gameId string) (BattleshipField, error) {
+	request := &pbbot.GetFieldRequest{GameId: gameId, OpponentName: OpponentNameFromContext(ctx)}
+
+	ctx, cancel, err := d.context(ctx, pbbot.BattleshipBotService_GetField_FullMethodName, gameId, request)
+	if err != nil {
+		return NewBattleshipField(), err
+	}
+	defer cancel()
+
+	resp, err := d.client.GetField(ctx, request)
+	if err != nil {
+		return NewBattleshipField(), err
+	}
+
+	return NewBattleshipFieldFromProto(&pbcore.BattleshipFieldProto{Field: resp.GetField()})
+}
+
+func (d *BotPlayerDriver) Strike(ctx context.Context, gameId string, own, other BattleshipField) (BattleshipPos, error) {
+	request := &pbbot.GetStrikeRequest{GameId: gameId, OwnField: own.ToProto(), OtherField: other.ToOtherFieldProto()}
+
+	ctx, cancel, err := d.context(ctx, pbbot.BattleshipBotService_GetStrike_FullMethodName, gameId, request)
+	if err != nil {
+		return BattleshipPos{}, err
+	}
+	defer cancel()
+
+	resp, err := d.client.GetStrike(ctx, request)
+	if err != nil {
+		return BattleshipPos{}, err
+	}
+
+	if resp.GetPos() == nil {
+		return BattleshipPos{}, errors.New("bot returned no strike position")
+	}
+
+	return NewBattleshipPosFromProto(resp.Pos), nil
+}
+
+// GameOver does nothing, as the unary API has no way to tell the bot that the
+// game is over. StreamingPlayerDriver sends game_over instead.
+func (d *BotPlayerDriver) GameOver(ctx context.Context, gameId string, won bool, opponent *BattleshipField) {
+}
+
+// PlayGameCloseTimeout is how long StreamingPlayerDriver waits for the bot
+// to end the stream once game_over is sent.
+const PlayGameCloseTimeout = time.Second
+
+// StreamingPlayerDriver plays games with a bot over PlayGame, one stream per
+// game, so the bot is told about strikes as they happen and gets game_over
+// with the opponent's field. Responses are awaited with the timeout of the
+// wrapped driver.
+type StreamingPlayerDriver struct {
+	driver *BotPlayerDriver
+
+	mu    sync.Mutex
+	games map[string]*playGameSession
+}
+
+type playGameSession struct {
+	stream    pbbot.BattleshipBotService_PlayGameClient
+	cancel    context.CancelFunc
+	sentOwn   gotils.Set[BattleshipPos]
+	sentOther gotils.Set[BattleshipPos]
+}
+
+func NewStreamingPlayerDriver(driver *BotPlayerDriver) *StreamingPlayerDriver {
+	d := &StreamingPlayerDriver{}
+	d.driver = driver
+	d.games = make(map[string]*playGameSession)
+
+	return d
+}
+
+func (d *StreamingPlayerDriver) Info(ctx context.Context) (*pbcore.BotInfoProto, error) {
+	return d.driver.Info(ctx)
+}
+
+func (d *StreamingPlayerDriver) Field(ctx context.Context, gameId string) (BattleshipField, error) {
+	s, err := d.open(ctx, gameId)
+	if err != nil {
+		return NewBattleshipField(), err
+	}
+
+	request := &pbbot.PlayGameRequest{Event: &pbbot.PlayGameRequest_GameStart{
+		GameStart: &pbbot.GameStartEvent{GameId: gameId, OpponentName: OpponentNameFromContext(ctx)},
+	}}
+
+	resp, err := d.request(ctx, s, request)
+	if err != nil {
+		return NewBattleshipField(), err
+	}
+
+	field, ok := resp.Action.(*pbbot.PlayGameResponse_Field)
+	if !ok {
+		return NewBattleshipField(), fmt.Errorf("expected field from the bot, got: %v", resp)
+	}
+
+	return NewBattleshipFieldFromProto(&pbcore.BattleshipFieldProto{Field: field.Field})
+}
+
+func (d *StreamingPlayerDriver) Strike(ctx context.Context, gameId string, own, other BattleshipField) (BattleshipPos, error) {
+	d.mu.Lock()
+	s := d.games[gameId]
+	d.mu.Unlock()
+
+	if s == nil {
+		return BattleshipPos{}, fmt.Errorf("game %v is not started", gameId)
+	}
+
+	if err := s.sendOutcomes(&other, &s.sentOther, true); err != nil {
+		return BattleshipPos{}, err
+	}
+
+	if err := s.sendOutcomes(&own, &s.sentOwn, false); err != nil {
+		return BattleshipPos{}, err
+	}
+
+	resp, err := d.request(ctx, s, &pbbot.PlayGameRequest{Event: &pbbot.PlayGameRequest_Turn{Turn: &pbbot.TurnEvent{}}})
+	if err != nil {
+		return BattleshipPos{}, err
+	}
+
+	strike, ok := resp.Action.(*pbbot.PlayGameResponse_Strike)
+	if !ok || strike.Strike == nil {
+		return BattleshipPos{}, fmt.Errorf("expected strike from the bot, got: %v", resp)
+	}
+
+	return NewBattleshipPosFromProto(strike.Strike), nil
+}
+
+func (d *StreamingPlayerDriver) GameOver(ctx context.Context, gameId string, won bool, opponent *BattleshipField) {
+	d.mu.Lock()
+	s := d.games[gameId]
+	delete(d.games, gameId)
+	d.mu.Unlock()
+
+	if s == nil {
+		return
+	}
+	defer s.cancel()
+
+	e := &pbbot.GameOverEvent{Won: won}
+	if opponent != nil {
+		e.OpponentField = opponent.ToProto()
+	}
+
+	if err := s.stream.Send(&pbbot.PlayGameRequest{Event: &pbbot.PlayGameRequest_GameOver{GameOver: e}}); err != nil {
+		return
+	}
+
+	if err := s.stream.CloseSend(); err != nil {
+		return
+	}
+
+	// give the bot a chance to handle game_over before the stream is cancelled
+	timer := time.AfterFunc(PlayGameCloseTimeout, s.cancel)
+	defer timer.Stop()
+
+	for {
+		if _, err := s.stream.Recv(); err != nil {
+			return
+		}
+	}
+}
+
+// open starts the stream of the game, replacing the one of a retried game.
+func (d *StreamingPlayerDriver) open(ctx context.Context, gameId string) (*playGameSession, error) {
+	// the stream outlives the request starting the game, GameOver closes it
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	md := metadata.Pairs(GameIdMetadataKey, gameId)
+
+	if d.driver.secret != "" {
+		signature, err := NewSignatureMetadata(d.driver.secret, pbbot.BattleshipBotService_PlayGame_FullMethodName, gameId, nil, time.Now())
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		md = metadata.Join(md, signature)
+	}
+
+	stream, err := d.driver.client.PlayGame(metadata.NewOutgoingContext(ctx, md))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	s := &playGameSession{}
+	s.stream = stream
+	s.cancel = cancel
+	s.sentOwn = gotils.NewSet[BattleshipPos]()
+	s.sentOther = gotils.NewSet[BattleshipPos]()
+
+	d.mu.Lock()
+	prev := d.games[gameId]
+	d.games[gameId] = s
+	d.mu.Unlock()
+
+	if prev != nil {
+		prev.cancel()
+	}
+
+	return s, nil
+}
+
+// request sends the event and waits for the response, cancelling the stream
+// if ctx is done or the bot doesn't respond in time.
+func (d *StreamingPlayerDriver) request(ctx context.Context, s *playGameSession, request *pbbot.PlayGameRequest) (*pbbot.PlayGameResponse, error) {
+	if err := s.stream.Send(request); err != nil {
+		return nil, err
+	}
+
+	stop := context.AfterFunc(ctx, s.cancel)
+	defer stop()
+
+	var timer *time.Timer
+	if d.driver.timeout > 0 {
+		timer = time.AfterFunc(d.driver.timeout, s.cancel)
+	}
+
+	resp, err := s.stream.Recv()
+
+	if timer != nil && !timer.Stop() {
+		return nil, fmt.Errorf("bot didn't respond within %v: %w", d.driver.timeout, context.DeadlineExceeded)
+	}
+
+	return resp, err
+}
+
+// sendOutcomes sends hits, then misses of the field not sent yet.
+func (s *playGameSession) sendOutcomes(field *BattleshipField, sent *gotils.Set[BattleshipPos], own bool) error {
+	for _, outcomes := range []struct {
+		positions []BattleshipPos
+		hit       bool
+	}{{field.Hits.Items(), true}, {field.Misses.Items(), false}} {
+		for _, pos := range outcomes.positions {
+			if sent.Has(pos) {
+				continue
+			}
+			sent.Add(pos)
+
+			e := &pbbot.StrikeOutcomeEvent{Pos: pos.ToProto(), Hit: outcomes.hit, Own: own}
+			if err := s.stream.Send(&pbbot.PlayGameRequest{Event: &pbbot.PlayGameRequest_StrikeOutcome{StrikeOutcome: e}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// StrategyPlayerDriver plays core strategies in process. It plays one game
+// at a time, as rng isn't safe for concurrent use.
+type StrategyPlayerDriver struct {
+	placement BattleshipPlacementStrategy
+	targeting BattleshipTargetingStrategy
+	rng       *rand.Rand
+}
+
+func NewStrategyPlayerDriver(placement BattleshipPlacementStrategy, targeting BattleshipTargetingStrategy, rng *rand.Rand) *StrategyPlayerDriver {
+	d := &StrategyPlayerDriver{}
+	d.placement = placement
+	d.targeting = targeting
+	d.rng = rng
+
+	return d
+}
+
+func (d *StrategyPlayerDriver) Info(ctx context.Context) (*pbcore.BotInfoProto, error) {
+	return nil, nil
+}
+
+func (d *StrategyPlayerDriver) Field(ctx context.Context, gameId string) (BattleshipField, error) {
+	return d.placement.Place(ctx, d.rng)
+}
+
+func (d *StrategyPlayerDriver) Strike(ctx context.Context, gameId string, own, other BattleshipField) (BattleshipPos, error) {
+	view := NewBattleshipOpponentViewFromField(other)
+
+	return d.targeting.Target(ctx, &view, d.rng)
+}
+
+func (d *StrategyPlayerDriver) GameOver(ctx context.Context, gameId string, won bool, opponent *BattleshipField) {
+}
+
+type ctxKeyOpponentName struct{}
+
+// ContextWithOpponentName returns a copy of ctx carrying the name of the
+// player's opponent, BotPlayerDriver passes it on to the bot.
+func ContextWithOpponentName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, ctxKeyOpponentName{}, name)
+}
+
+// OpponentNameFromContext returns the name set by ContextWithOpponentName,
+// or an empty string.
+func OpponentNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(ctxKeyOpponentName{}).(string)
+	return name
+}
+
+// OpponentNamedDriver tells the driver it wraps the name of the opponent,
+// so bots can model their opponents across games.
+type OpponentNamedDriver struct {
+	PlayerDriver
+	OpponentName string
+}
+
+func NewOpponentNamedDriver(driver PlayerDriver, opponentName string) *OpponentNamedDriver {
+	d := &OpponentNamedDriver{}
+	d.PlayerDriver = driver
+	d.OpponentName = opponentName
+
+	return d
+}
+
+func (d *OpponentNamedDriver) Field(ctx context.Context, gameId string) (BattleshipField, error) {
+	return d.PlayerDriver.Field(ContextWithOpponentName(ctx, d.OpponentName), gameId)
+}
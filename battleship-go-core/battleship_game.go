@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+)
+
+type BattleshipGameStateKind int
+
+const (
+	BattleshipGameStateAwaitingField BattleshipGameStateKind = iota
+	BattleshipGameStateAwaitingStrike
+	BattleshipGameStateGameOver
+)
+
+// BattleshipGameState is the action the game expects next. PlayerId is the
+// player to provide a field, the attacker, or the winner once the game is over.
+type BattleshipGameState struct {
+	Kind     BattleshipGameStateKind
+	PlayerId string
+}
+
+func (s BattleshipGameState) String() string {
+	switch s.Kind {
+	case BattleshipGameStateAwaitingField:
+		return fmt.Sprintf("AwaitingField[%v]", s.PlayerId)
+	case BattleshipGameStateAwaitingStrike:
+		return fmt.Sprintf("AwaitingStrike[%v]", s.PlayerId)
+	default:
+		return fmt.Sprintf("GameOver[%v]", s.PlayerId)
+	}
+}
+
+type BattleshipActionField struct {
+	PlayerId string
+	Field    BattleshipField
+}
+
+type BattleshipActionStrike struct {
+	AttackerId string
+	Pos        BattleshipPos
+}
+
+type BattleshipActionGameOver struct {
+	WinnerId string
+}
+
+// BattleshipGameLogEntry is either an accepted action or an error, exactly
+// one of the fields is set.
+type BattleshipGameLogEntry struct {
+	Field    *BattleshipActionField
+	Strike   *BattleshipActionStrike
+	GameOver *BattleshipActionGameOver
+	Error    string
+}
+
+// ToProto converts the entry to the form served by the server's GetGame.
+func (e BattleshipGameLogEntry) ToProto() *pbserver.GameLogEntryProto {
+	p := pbserver.GameLogEntryProto{}
+
+	switch {
+	case e.Field != nil:
+		field := pbcore.BattleshipFieldProto{Field: e.Field.Field.ToProto().Field}
+		p.Action = &pbserver.GameLogEntryProto_Field{Field: &pbcore.BattleshipActionFieldProto{PlayerId: e.Field.PlayerId, Field: &field}}
+	case e.Strike != nil:
+		p.Action = &pbserver.GameLogEntryProto_Strike{Strike: &pbcore.BattleshipActionStrikeProto{AttackerId: e.Strike.AttackerId, Position: e.Strike.Pos.ToProto()}}
+	case e.GameOver != nil:
+		p.Action = &pbserver.GameLogEntryProto_GameOver{GameOver: &pbcore.BattleshipActionGameOverProto{WinnerId: e.GameOver.WinnerId}}
+	default:
+		p.Action = &pbserver.GameLogEntryProto_Error{Error: e.Error}
+	}
+
+	return &p
+}
+
+// BattleshipGame is a game between two players, player 1 provides the field
+// and strikes first. Mirrors the rules of the Kotlin server's BattleshipGame.
+type BattleshipGame struct {
+	Id           string
+	Player1Id    string
+	Player2Id    string
+	Player1Field *BattleshipField
+	Player2Field *BattleshipField
+	State        BattleshipGameState
+	Log          []BattleshipGameLogEntry
+}
+
+func NewBattleshipGame(id, player1Id, player2Id string) *BattleshipGame {
+	g := &BattleshipGame{}
+	g.Id = id
+	g.Player1Id = player1Id
+	g.Player2Id = player2Id
+	g.State = BattleshipGameState{Kind: BattleshipGameStateAwaitingField, PlayerId: player1Id}
+	g.Log = make([]BattleshipGameLogEntry, 0)
+
+	return g
+}
+
+// AcceptField validates and records the field of the player. Hits and
+// misses of the field are ignored.
+func (g *BattleshipGame) AcceptField(playerId string, field BattleshipField) error {
+	if err := field.Validate(); err != nil {
+		return err
+	}
+
+	f := NewBattleshipField()
+	f.Field = field.Field
+
+	g.Log = append(g.Log, BattleshipGameLogEntry{Field: &BattleshipActionField{PlayerId: playerId, Field: f}})
+
+	if g.State.Kind != BattleshipGameStateAwaitingField {
+		return fmt.Errorf("expected current game state to be AwaitingField, got %v", g.State)
+	}
+
+	if g.State.PlayerId != playerId {
+		return fmt.Errorf("unexpected player turn to provide field: expected %v, got: %v", g.State.PlayerId, playerId)
+	}
+
+	if playerId == g.Player1Id {
+		g.Player1Field = &f
+		g.State = BattleshipGameState{Kind: BattleshipGameStateAwaitingField, PlayerId: g.Player2Id}
+	} else {
+		g.Player2Field = &f
+		g.State = BattleshipGameState{Kind: BattleshipGameStateAwaitingStrike, PlayerId: g.Player1Id}
+	}
+
+	return nil
+}
+
+// AcceptStrike records the strike of the attacker, passing the turn to the
+// other player, or ending the game once the other player's fleet is sunk.
+func (g *BattleshipGame) AcceptStrike(attackerId string, pos BattleshipPos) error {
+	g.Log = append(g.Log, BattleshipGameLogEntry{Strike: &BattleshipActionStrike{AttackerId: attackerId, Pos: pos}})
+
+	if g.State.Kind != BattleshipGameStateAwaitingStrike {
+		return fmt.Errorf("expected current game state to be AwaitingStrike, got %v", g.State)
+	}
+
+	if g.State.PlayerId != attackerId {
+		return fmt.Errorf("unexpected player turn to strike: expected %v, got: %v", g.State.PlayerId, attackerId)
+	}
+
+	if !pos.IsInBounds() {
+		return fmt.Errorf("strike position is out of bounds: %v", pos)
+	}
+
+	victimId := g.OtherPlayerId(attackerId)
+	victimField := g.PlayerField(victimId)
+
+	victimField.Strike(pos)
+
+	if victimField.HasAliveShips() {
+		g.State = BattleshipGameState{Kind: BattleshipGameStateAwaitingStrike, PlayerId: victimId}
+	} else {
+		g.State = BattleshipGameState{Kind: BattleshipGameStateGameOver, PlayerId: attackerId}
+		g.Log = append(g.Log, BattleshipGameLogEntry{GameOver: &BattleshipActionGameOver{WinnerId: attackerId}})
+	}
+
+	return nil
+}
+
+func (g *BattleshipGame) AppendError(err error) {
+	g.Log = append(g.Log, BattleshipGameLogEntry{Error: err.Error()})
+}
+
+func (g *BattleshipGame) IsOver() bool {
+	return g.State.Kind == BattleshipGameStateGameOver
+}
+
+// WinnerId returns the winner of a finished game.
+func (g *BattleshipGame) WinnerId() (string, bool) {
+	if !g.IsOver() {
+		return "", false
+	}
+
+	return g.State.PlayerId, true
+}
+
+func (g *BattleshipGame) OtherPlayerId(playerId string) string {
+	if playerId == g.Player1Id {
+		return g.Player2Id
+	}
+
+	return g.Player1Id
+}
+
+func (g *BattleshipGame) PlayerField(playerId string) *BattleshipField {
+	if playerId == g.Player1Id {
+		return g.Player1Field
+	}
+
+	return g.Player2Field
+}
+
+func (g *BattleshipGame) LogToProto() []*pbserver.GameLogEntryProto {
+	log := make([]*pbserver.GameLogEntryProto, 0, len(g.Log))
+
+	for _, e := range g.Log {
+		log = append(log, e.ToProto())
+	}
+
+	return log
+}
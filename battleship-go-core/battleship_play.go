@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+)
+
+// BattleshipGameTurnsLimit is the number of turns after which a game is aborted.
+const BattleshipGameTurnsLimit = 10_000
+
+var ErrGameTooLong = errors.New("Game was taking too long, aborted")
+
+// PlayerDriver makes moves on behalf of a player, usually by calling a bot.
+type PlayerDriver interface {
+	// Info returns nil if the bot doesn't implement GetInfo.
+	Info(ctx context.Context) (*pbcore.BotInfoProto, error)
+	Field(ctx context.Context, gameId string) (BattleshipField, error)
+	// Strike gets the player's own field and hits and misses of the other player's field.
+	Strike(ctx context.Context, gameId string, own, other BattleshipField) (BattleshipPos, error)
+	// GameOver is called once the game is finished, or failed. Opponent is
+	// the opponent's field, nil if it never placed one.
+	GameOver(ctx context.Context, gameId string, won bool, opponent *BattleshipField)
+}
+
+// PlayBattleshipGame asks players for moves until the game is over. A failed
+// move or reaching BattleshipGameTurnsLimit ends the game and the error is
+// returned. Like the server, the error of a failed move is appended to the
+// game log, followed by ErrGameTooLong for every game that isn't over.
+func PlayBattleshipGame(ctx context.Context, g *BattleshipGame, players map[string]PlayerDriver) error {
+	err := playBattleshipGame(ctx, g, players)
+
+	if err != nil {
+		g.AppendError(err)
+	}
+
+	if !g.IsOver() {
+		g.AppendError(ErrGameTooLong)
+
+		if err == nil {
+			err = ErrGameTooLong
+		}
+	}
+
+	winnerId, _ := g.WinnerId()
+
+	for id, p := range players {
+		p.GameOver(context.WithoutCancel(ctx), g.Id, id == winnerId, g.PlayerField(g.OtherPlayerId(id)))
+	}
+
+	return err
+}
+
+func playBattleshipGame(ctx context.Context, g *BattleshipGame, players map[string]PlayerDriver) error {
+	for turn := 0; turn <= BattleshipGameTurnsLimit; turn += 1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		switch g.State.Kind {
+		case BattleshipGameStateAwaitingField:
+			field, err := players[g.State.PlayerId].Field(ctx, g.Id)
+			if err != nil {
+				return err
+			}
+
+			if err := g.AcceptField(g.State.PlayerId, field); err != nil {
+				return err
+			}
+		case BattleshipGameStateAwaitingStrike:
+			attackerId := g.State.PlayerId
+			other := g.PlayerField(g.OtherPlayerId(attackerId))
+
+			// only hits and misses of the other field are known to the attacker
+			view := NewBattleshipField()
+			for _, pos := range other.Hits.Items() {
+				view.Hits.Add(pos)
+			}
+			for _, pos := range other.Misses.Items() {
+				view.Misses.Add(pos)
+			}
+
+			pos, err := players[attackerId].Strike(ctx, g.Id, *g.PlayerField(attackerId), view)
+			if err != nil {
+				return err
+			}
+
+			if err := g.AcceptStrike(attackerId, pos); err != nil {
+				return err
+			}
+		case BattleshipGameStateGameOver:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// NewId returns a random id, formatted the same way as ids of the Kotlin server.
+func NewId() string {
+	b := make([]byte, 16)
+
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+
+	return base64.RawStdEncoding.EncodeToString(b)
+}
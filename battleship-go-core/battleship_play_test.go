@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+)
+
+// failingDriver fails to place its field.
+type failingDriver struct {
+	err error
+}
+
+func (d failingDriver) Info(ctx context.Context) (*pbcore.BotInfoProto, error) {
+	return nil, nil
+}
+
+func (d failingDriver) Field(ctx context.Context, gameId string) (BattleshipField, error) {
+	return NewBattleshipField(), d.err
+}
+
+func (d failingDriver) Strike(ctx context.Context, gameId string, own, other BattleshipField) (BattleshipPos, error) {
+	return BattleshipPos{}, d.err
+}
+
+func (d failingDriver) GameOver(ctx context.Context, gameId string, won bool, opponent *BattleshipField) {
+}
+
+func TestPlayBattleshipGameLogsFailures(t *testing.T) {
+	failure := errors.New("bot is down")
+
+	g := NewBattleshipGame(NewId(), "first", "second")
+	players := map[string]PlayerDriver{
+		"first":  failingDriver{failure},
+		"second": failingDriver{failure},
+	}
+
+	if err := PlayBattleshipGame(context.Background(), g, players); !errors.Is(err, failure) {
+		t.Fatalf("expected %v, got %v", failure, err)
+	}
+
+	errs := make([]string, 0)
+	for _, e := range g.Log {
+		if e.Error != "" {
+			errs = append(errs, e.Error)
+		}
+	}
+
+	// the server logs the failure and aborts every game that isn't over
+	if expected := []string{failure.Error(), ErrGameTooLong.Error()}; !slices.Equal(errs, expected) {
+		t.Fatalf("expected log errors %q, got %q", expected, errs)
+	}
+}
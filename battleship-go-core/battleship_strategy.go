@@ -0,0 +1,290 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/maps"
+)
+
+type BattleshipOpponentTile int
+
+const (
+	BattleshipOpponentTileUnknown BattleshipOpponentTile = iota
+	BattleshipOpponentTileHit
+	BattleshipOpponentTileMiss
+)
+
+// BattleshipOpponentView is everything an attacker knows about the opponent's
+// field: which positions were hit or missed.
+type BattleshipOpponentView struct {
+	Tiles [BattleshipFieldSize][BattleshipFieldSize]BattleshipOpponentTile
+}
+
+func NewBattleshipOpponentView() BattleshipOpponentView {
+	return BattleshipOpponentView{}
+}
+
+// NewBattleshipOpponentViewFromField creates a view from hits and misses of the given field.
+func NewBattleshipOpponentViewFromField(f BattleshipField) BattleshipOpponentView {
+	v := NewBattleshipOpponentView()
+
+	for _, h := range f.Hits.Items() {
+		v.Mark(h, true)
+	}
+
+	for _, m := range f.Misses.Items() {
+		v.Mark(m, false)
+	}
+
+	return v
+}
+
+func (v *BattleshipOpponentView) Tile(pos BattleshipPos) BattleshipOpponentTile {
+	return v.Tiles[pos.Y][pos.X]
+}
+
+func (v *BattleshipOpponentView) IsUnknown(pos BattleshipPos) bool {
+	return pos.IsInBounds() && v.Tile(pos) == BattleshipOpponentTileUnknown
+}
+
+func (v *BattleshipOpponentView) Mark(pos BattleshipPos, hit bool) {
+	if !pos.IsInBounds() {
+		return
+	}
+
+	if hit {
+		v.Tiles[pos.Y][pos.X] = BattleshipOpponentTileHit
+	} else {
+		v.Tiles[pos.Y][pos.X] = BattleshipOpponentTileMiss
+	}
+}
+
+// Unknown returns all positions that weren't struck yet, ordered top to bottom, left to right.
+func (v *BattleshipOpponentView) Unknown() []BattleshipPos {
+	ps := make([]BattleshipPos, 0, BattleshipFieldSize*BattleshipFieldSize)
+
+	for y, l := range v.Tiles {
+		for x, t := range l {
+			if t == BattleshipOpponentTileUnknown {
+				ps = append(ps, BattleshipPos{X: x, Y: y})
+			}
+		}
+	}
+
+	return ps
+}
+
+func (v *BattleshipOpponentView) HitsCount() int {
+	count := 0
+
+	for _, l := range v.Tiles {
+		for _, t := range l {
+			if t == BattleshipOpponentTileHit {
+				count += 1
+			}
+		}
+	}
+
+	return count
+}
+
+// BattleshipPlacementStrategy places a fleet at the start of a game.
+type BattleshipPlacementStrategy interface {
+	Place(ctx context.Context, rng *rand.Rand) (BattleshipField, error)
+}
+
+// BattleshipTargetingStrategy chooses the next position to strike.
+type BattleshipTargetingStrategy interface {
+	Target(ctx context.Context, view *BattleshipOpponentView, rng *rand.Rand) (BattleshipPos, error)
+}
+
+// BattleshipStrategyParam is a numeric parameter of a tunable strategy.
+type BattleshipStrategyParam struct {
+	Name  string
+	Value float64
+	Min   float64
+	Max   float64
+	// Step is a meaningful change of the value, used as the perturbation
+	// size when tuning.
+	Step    float64
+	Integer bool
+}
+
+// BattleshipTunableStrategy is a placement or targeting strategy with
+// numeric parameters, e.g. the weight of hits in density targeting.
+type BattleshipTunableStrategy interface {
+	Params() []BattleshipStrategyParam
+	SetParam(name string, value float64) error
+}
+
+// StrategyParams returns parameters of the strategy, or none if it isn't tunable.
+func StrategyParams(strategy any) []BattleshipStrategyParam {
+	if t, ok := strategy.(BattleshipTunableStrategy); ok {
+		return t.Params()
+	}
+
+	return []BattleshipStrategyParam{}
+}
+
+// SetStrategyParams sets parameters of the strategy by name, checking they
+// exist and are in range.
+func SetStrategyParams(strategy any, params map[string]float64) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	t, ok := strategy.(BattleshipTunableStrategy)
+	if !ok {
+		return fmt.Errorf("strategy has no parameters, got %v", sortedKeys(params))
+	}
+
+	known := make(map[string]BattleshipStrategyParam)
+	for _, p := range t.Params() {
+		known[p.Name] = p
+	}
+
+	// reported on one line, as they are wrapped with the strategy name
+	problems := make([]string, 0)
+
+	for _, name := range sortedKeys(params) {
+		value := params[name]
+		p, ok := known[name]
+
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("unknown parameter %q, expected one of %v", name, sortedKeys(known)))
+		case value < p.Min || value > p.Max:
+			problems = append(problems, fmt.Sprintf("parameter %v must be between %v and %v, got %v", name, p.Min, p.Max, value))
+		case p.Integer && value != math.Trunc(value):
+			problems = append(problems, fmt.Sprintf("parameter %v must be an integer, got %v", name, value))
+		default:
+			if err := t.SetParam(name, value); err != nil {
+				problems = append(problems, err.Error())
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+type BattleshipPlacementStrategyFactory func() BattleshipPlacementStrategy
+
+type BattleshipTargetingStrategyFactory func() BattleshipTargetingStrategy
+
+var (
+	strategiesMu        sync.RWMutex
+	placementStrategies = make(map[string]BattleshipPlacementStrategyFactory)
+	targetingStrategies = make(map[string]BattleshipTargetingStrategyFactory)
+)
+
+// RegisterPlacementStrategy makes a placement strategy available by name.
+// It panics if the name is already registered.
+func RegisterPlacementStrategy(name string, factory BattleshipPlacementStrategyFactory) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	if _, ok := placementStrategies[name]; ok {
+		panic(fmt.Sprintf("placement strategy %q is already registered", name))
+	}
+
+	placementStrategies[name] = factory
+}
+
+// RegisterTargetingStrategy makes a targeting strategy available by name.
+// It panics if the name is already registered.
+func RegisterTargetingStrategy(name string, factory BattleshipTargetingStrategyFactory) {
+	strategiesMu.Lock()
+	defer strategiesMu.Unlock()
+
+	if _, ok := targetingStrategies[name]; ok {
+		panic(fmt.Sprintf("targeting strategy %q is already registered", name))
+	}
+
+	targetingStrategies[name] = factory
+}
+
+func NewPlacementStrategy(name string) (BattleshipPlacementStrategy, error) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	factory, ok := placementStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown placement strategy %q, expected one of %v", name, sortedKeys(placementStrategies))
+	}
+
+	return factory(), nil
+}
+
+func NewTargetingStrategy(name string) (BattleshipTargetingStrategy, error) {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	factory, ok := targetingStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown targeting strategy %q, expected one of %v", name, sortedKeys(targetingStrategies))
+	}
+
+	return factory(), nil
+}
+
+// NewPlacementStrategyWithParams looks up a placement strategy by name and
+// sets its parameters.
+func NewPlacementStrategyWithParams(name string, params map[string]float64) (BattleshipPlacementStrategy, error) {
+	s, err := NewPlacementStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SetStrategyParams(s, params); err != nil {
+		return nil, fmt.Errorf("placement strategy %q: %w", name, err)
+	}
+
+	return s, nil
+}
+
+// NewTargetingStrategyWithParams looks up a targeting strategy by name and
+// sets its parameters.
+func NewTargetingStrategyWithParams(name string, params map[string]float64) (BattleshipTargetingStrategy, error) {
+	s, err := NewTargetingStrategy(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SetStrategyParams(s, params); err != nil {
+		return nil, fmt.Errorf("targeting strategy %q: %w", name, err)
+	}
+
+	return s, nil
+}
+
+func PlacementStrategyNames() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	return sortedKeys(placementStrategies)
+}
+
+func TargetingStrategyNames() []string {
+	strategiesMu.RLock()
+	defer strategiesMu.RUnlock()
+
+	return sortedKeys(targetingStrategies)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+
+	return keys
+}
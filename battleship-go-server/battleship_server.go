@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// ConfigEnv names the environment variable pointing to the server config file.
+const ConfigEnv = "BATTLESHIP_SERVER_GO_CONFIG"
+
+const MinSecretLength = 16
+
+type Config struct {
+	GrpcHost       string        `yaml:"grpc_host" toml:"grpc_host" env:"BATTLESHIP_SERVER_GRPC_HOST" flag:"grpc-host" usage:"host to serve the server gRPC API at"`
+	GrpcPort       string        `yaml:"grpc_port" toml:"grpc_port" env:"BATTLESHIP_SERVER_GRPC_PORT" flag:"grpc-port" usage:"port to serve the server gRPC API at"`
+	RequireSecret  bool          `yaml:"require_secret" toml:"require_secret" env:"BATTLESHIP_SERVER_LOBBY_REQUIRE_SECRET" flag:"require-secret" usage:"reject bots joining the lobby without a secret"`
+	RequestTimeout time.Duration `yaml:"request_timeout" toml:"request_timeout" env:"BATTLESHIP_SERVER_GO_REQUEST_TIMEOUT" flag:"request-timeout" usage:"timeout of requests to bots"`
+	LogLevel       string        `yaml:"log_level" toml:"log_level" env:"BATTLESHIP_SERVER_GO_LOG_LEVEL" flag:"log-level" usage:"log level: debug, info, warn or error"`
+	BotsTLS        bool          `yaml:"bots_tls" toml:"bots_tls" env:"BATTLESHIP_SERVER_BOTS_TLS" flag:"bots-tls" usage:"connect to bots over TLS"`
+	BotsCAFile     string        `yaml:"bots_ca_file" toml:"bots_ca_file" env:"BATTLESHIP_SERVER_BOTS_TLS_CA_FILE" flag:"bots-ca-file" usage:"CA certificates to verify bot certificates with, instead of system ones"`
+	BotsCertFile   string        `yaml:"bots_cert_file" toml:"bots_cert_file" env:"BATTLESHIP_SERVER_BOTS_TLS_CERT_FILE" flag:"bots-cert-file" usage:"client certificate presented to bots requiring mutual TLS"`
+	BotsKeyFile    string        `yaml:"bots_key_file" toml:"bots_key_file" env:"BATTLESHIP_SERVER_BOTS_TLS_KEY_FILE" flag:"bots-key-file" usage:"client certificate key"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.GrpcHost = "localhost"
+	c.GrpcPort = "6969"
+	c.RequireSecret = false
+	c.RequestTimeout = 10 * time.Second
+	c.LogLevel = "info"
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if err := core.ValidatePort(c.GrpcPort); err != nil {
+		errs = append(errs, fmt.Errorf("grpc_port: %w", err))
+	}
+
+	if c.RequestTimeout <= 0 {
+		errs = append(errs, errors.New("request_timeout must be positive"))
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		errs = append(errs, fmt.Errorf("log_level: %w", err))
+	}
+
+	if (c.BotsCertFile == "") != (c.BotsKeyFile == "") {
+		errs = append(errs, errors.New("bots_cert_file and bots_key_file must be set together"))
+	}
+
+	if !c.BotsTLS && (c.BotsCAFile != "" || c.BotsCertFile != "") {
+		errs = append(errs, errors.New("bots_ca_file, bots_cert_file and bots_key_file require bots_tls"))
+	}
+
+	if _, err := c.BotDialOptions(); err != nil {
+		errs = append(errs, fmt.Errorf("bots tls: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// BotDialOptions returns options of connections to bots.
+func (c Config) BotDialOptions() ([]grpc.DialOption, error) {
+	if !c.BotsTLS {
+		return []grpc.DialOption{}, nil
+	}
+
+	config, err := core.NewClientTLSConfig(c.BotsCAFile, c.BotsCertFile, c.BotsKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}, nil
+}
+
+// Server is a stand-in for the Kotlin server, to develop and test Go bots and
+// the CLI without the JVM. State is kept in memory only.
+type Server struct {
+	pbserver.UnimplementedBattleshipServerServiceServer
+
+	config           Config
+	botDialOptions   []grpc.DialOption
+	logger           *slog.Logger
+	games            *GameRepository
+	lobby            *Lobby
+	randomBotCounter atomic.Int64
+}
+
+func NewServer(ctx context.Context, config Config, logger *slog.Logger) (*Server, error) {
+	botDialOptions, err := config.BotDialOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{}
+	s.config = config
+	s.botDialOptions = botDialOptions
+	s.logger = logger
+	s.games = NewGameRepository()
+	s.lobby = NewLobby(ctx, s.games, logger)
+
+	return s, nil
+}
+
+func (s *Server) JoinLobby(ctx context.Context, request *pbserver.JoinLobbyRequest) (*pbserver.JoinLobbyResponse, error) {
+	s.logger.Info(fmt.Sprintf("joinLobby: addr=%v name=%v", request.Addr, request.Name))
+
+	if request.Addr == "" {
+		return nil, status.Error(codes.InvalidArgument, "addr must not be empty")
+	}
+
+	if err := s.validateJoin(request); err != nil {
+		return nil, err
+	}
+
+	driver, err := core.DialBotPlayerDriver(request.Addr, request.Secret, s.config.RequestTimeout, s.botDialOptions...)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to connect to %v: %v", request.Addr, err)
+	}
+
+	if err := s.join(ctx, request.Addr, request.Name, request.Secret, driver); err != nil {
+		driver.Close()
+		return nil, err
+	}
+
+	return &pbserver.JoinLobbyResponse{}, nil
+}
+
+func (s *Server) GetGames(ctx context.Context, request *pbserver.GetGamesRequest) (*pbserver.GetGamesResponse, error) {
+	s.logger.Info(fmt.Sprintf("getGames: %v", request))
+
+	players := s.lobby.Players()
+	games := make([]*pbserver.GetGamesResponseEntry, 0)
+
+	for _, g := range s.games.FindAll() {
+		entry := &pbserver.GetGamesResponseEntry{}
+		entry.Id = g.Id
+		entry.State = g.State
+		entry.Player_1 = players[g.Player1Id]
+		entry.Player_2 = players[g.Player2Id]
+
+		if winnerId, ok := g.WinnerId(); ok {
+			entry.WinnerId = &winnerId
+		}
+
+		games = append(games, entry)
+	}
+
+	return &pbserver.GetGamesResponse{Games: games}, nil
+}
+
+func (s *Server) GetGame(ctx context.Context, request *pbserver.GetGameRequest) (*pbserver.GetGameResponse, error) {
+	s.logger.Info(fmt.Sprintf("getGame: %v", request))
+
+	g, ok := s.games.FindById(request.Id)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "game %v not found", request.Id)
+	}
+
+	players := s.lobby.Players()
+
+	game := &pbserver.GameProto{}
+	game.Id = g.Id
+	game.Player_1 = players[g.Player1Id]
+	game.Player_2 = players[g.Player2Id]
+	game.State = g.State
+	game.Log = make([]*pbserver.GameLogEntryProto, 0, len(g.Log))
+
+	for _, e := range g.Log {
+		game.Log = append(game.Log, e.ToProto())
+	}
+
+	return &pbserver.GetGameResponse{Game: game}, nil
+}
+
+func (s *Server) AddRandomBot(ctx context.Context, request *pbserver.AddRandomBotRequest) (*pbserver.AddRandomBotResponse, error) {
+	s.logger.Info(fmt.Sprintf("addRandomBot: %v", request))
+
+	ps := []string{"Mighty", "Funny", "Clever", "Handsome", "Tiny", "Pink"}
+	ss := []string{"Cat", "Puppy", "Parrot", "Pony", "Bear", "Mouse", "Snake"}
+	name := fmt.Sprintf("%v %v %v", ps[rand.Intn(len(ps))], ss[rand.Intn(len(ss))], s.randomBotCounter.Add(1))
+
+	if err := s.join(ctx, RandomBotAddrPrefix+name, name, "", NewRandomPlayerDriver(name)); err != nil {
+		return nil, err
+	}
+
+	return &pbserver.AddRandomBotResponse{}, nil
+}
+
+func (s *Server) Connect(stream pbserver.BattleshipServerService_ConnectServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	join := msg.GetJoin()
+	if join == nil {
+		return status.Error(codes.InvalidArgument, "first message must join the lobby")
+	}
+
+	s.logger.Info(fmt.Sprintf("connect: name=%v", join.Name))
+
+	if err := s.validateJoin(join); err != nil {
+		return err
+	}
+
+	conn := NewReverseConnection(stream, s.logger)
+	driver := core.NewBotPlayerDriver(conn, "", s.config.RequestTimeout)
+	served := make(chan error, 1)
+
+	// responses to GetInfo sent while joining are received by Serve
+	go func() {
+		served <- conn.Serve()
+	}()
+
+	player, rejoined, err := s.lobby.Join(ReverseAddr(stream.Context()), join.Name, join.Secret, driver)
+	if err != nil {
+		return joinError(err)
+	}
+	defer s.lobby.Leave(player, driver)
+
+	conn.sendMu.Lock()
+	err = stream.Send(&pbserver.ConnectResponse{})
+	conn.sendMu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	s.lobby.Schedule(stream.Context(), player, rejoined)
+
+	return <-served
+}
+
+func (s *Server) join(ctx context.Context, addr, name, secret string, driver core.PlayerDriver) error {
+	player, rejoined, err := s.lobby.Join(addr, name, secret, driver)
+	if err != nil {
+		return joinError(err)
+	}
+
+	s.lobby.Schedule(ctx, player, rejoined)
+
+	return nil
+}
+
+func (s *Server) validateJoin(join *pbserver.JoinLobbyRequest) error {
+	if join.Name == "" {
+		return status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+
+	if join.Secret == "" && s.config.RequireSecret {
+		return status.Error(codes.InvalidArgument, "joining the lobby requires a secret")
+	}
+
+	if join.Secret != "" && len(join.Secret) < MinSecretLength {
+		return status.Errorf(codes.InvalidArgument, "secret must be at least %v characters long", MinSecretLength)
+	}
+
+	return nil
+}
+
+func joinError(err error) error {
+	if errors.Is(err, ErrNameTaken) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	return err
+}
+
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	var level slog.Level
+	level.UnmarshalText([]byte(config.LogLevel))
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	grpcUrl := fmt.Sprintf("%v:%v", config.GrpcHost, config.GrpcPort)
+	lis, err := net.Listen("tcp", grpcUrl)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to listen: %v", err))
+		os.Exit(1)
+	}
+
+	server, err := NewServer(ctx, config, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to create server: %v", err))
+		os.Exit(1)
+	}
+
+	grpcServer := grpc.NewServer()
+	pbserver.RegisterBattleshipServerServiceServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	go func() {
+		<-ctx.Done()
+		logger.Info("Shutting down gRPC server")
+		grpcServer.Stop()
+	}()
+
+	logger.Info(fmt.Sprintf("Starting gRPC server at: %v", grpcUrl))
+
+	if err := grpcServer.Serve(lis); err != nil {
+		logger.Error(fmt.Sprintf("failed to serve gRPC: %v", err))
+		os.Exit(1)
+	}
+
+	server.lobby.Wait()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+)
+
+type Game struct {
+	Id        string
+	Player1Id string
+	Player2Id string
+	State     pbserver.GameStateProto
+	// Log is set once the game is finished.
+	Log []core.BattleshipGameLogEntry
+}
+
+// WinnerId returns the winner of a finished game, if it wasn't aborted.
+func (g Game) WinnerId() (string, bool) {
+	if g.State != pbserver.GameStateProto_FINISHED || len(g.Log) == 0 {
+		return "", false
+	}
+
+	if last := g.Log[len(g.Log)-1]; last.GameOver != nil {
+		return last.GameOver.WinnerId, true
+	}
+
+	return "", false
+}
+
+// GameRepository keeps games in memory, in the order they were created.
+type GameRepository struct {
+	mu    sync.Mutex
+	games []Game
+	index map[string]int
+}
+
+func NewGameRepository() *GameRepository {
+	r := &GameRepository{}
+	r.games = make([]Game, 0)
+	r.index = make(map[string]int)
+
+	return r
+}
+
+func (r *GameRepository) Create(player1Id, player2Id string) Game {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g := Game{}
+	g.Id = core.NewId()
+	g.Player1Id = player1Id
+	g.Player2Id = player2Id
+	g.State = pbserver.GameStateProto_IDLE
+	g.Log = make([]core.BattleshipGameLogEntry, 0)
+
+	r.index[g.Id] = len(r.games)
+	r.games = append(r.games, g)
+
+	return g
+}
+
+func (r *GameRepository) Update(g Game) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.index[g.Id]
+	if !ok {
+		return fmt.Errorf("game %v doesn't exist", g.Id)
+	}
+
+	r.games[i] = g
+
+	return nil
+}
+
+func (r *GameRepository) FindById(id string) (Game, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.index[id]
+	if !ok {
+		return Game{}, false
+	}
+
+	return r.games[i], true
+}
+
+func (r *GameRepository) FindAll() []Game {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	games := make([]Game, len(r.games))
+	copy(games, r.games)
+
+	return games
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+)
+
+// RoundsCount is the number of games scheduled between every two players.
+const RoundsCount = 3
+
+const GetInfoTimeout = 5 * time.Second
+
+var ErrNameTaken = errors.New("player name is already taken")
+
+type Player struct {
+	Id     string
+	Addr   string
+	Name   string
+	Secret string
+	Info   *pbcore.BotInfoProto
+	// Driver is nil while the player is disconnected.
+	Driver core.PlayerDriver
+}
+
+func (p *Player) String() string {
+	return fmt.Sprintf("Player(id=%v, addr=%v, name=%v)", p.Id, p.Addr, p.Name)
+}
+
+func (p *Player) ToProto() *pbserver.PlayerProto {
+	return &pbserver.PlayerProto{Id: p.Id, Name: p.Name, Info: p.Info}
+}
+
+// Supports reports whether the player can play the ruleset and variant,
+// players without info are assumed to play only the default ones.
+func (p *Player) Supports(ruleset, variant string) bool {
+	if p.Info == nil {
+		return ruleset == core.BattleshipDefaultRuleset && variant == core.BattleshipDefaultVariant
+	}
+
+	return slices.Contains(p.Info.Rulesets, ruleset) && slices.Contains(p.Info.Variants, variant)
+}
+
+// HasCapability reports whether the player's info lists the capability.
+func (p *Player) HasCapability(capability string) bool {
+	return p.Info != nil && slices.Contains(p.Info.Capabilities, capability)
+}
+
+// Lobby keeps joined players and schedules games between them.
+type Lobby struct {
+	runner *GameRunner
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	players []*Player
+}
+
+// NewLobby creates a lobby running its games until ctx is done.
+func NewLobby(ctx context.Context, games *GameRepository, logger *slog.Logger) *Lobby {
+	l := &Lobby{}
+	l.runner = NewGameRunner(ctx, l, games, logger)
+	l.logger = logger
+	l.players = make([]*Player, 0)
+
+	return l
+}
+
+// Join adds a player driven by driver, reporting whether it has rejoined:
+// a name joined with a secret is reserved, joining with the same name and
+// secret again only replaces the address and the driver.
+func (l *Lobby) Join(addr, name, secret string, driver core.PlayerDriver) (*Player, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range l.players {
+		if p.Name != name {
+			continue
+		}
+
+		if p.Secret == "" || subtle.ConstantTimeCompare([]byte(p.Secret), []byte(secret)) != 1 {
+			return nil, false, fmt.Errorf("%w: %v", ErrNameTaken, name)
+		}
+
+		if c, ok := p.Driver.(io.Closer); ok {
+			c.Close()
+		}
+
+		p.Addr = addr
+		p.Driver = driver
+
+		return p, true, nil
+	}
+
+	player := &Player{Id: core.NewId(), Addr: addr, Name: name, Secret: secret, Driver: driver}
+	l.players = append(l.players, player)
+
+	return player, false, nil
+}
+
+// Schedule requests the info of the joined player and, unless it has
+// rejoined, schedules games against every other connected player supporting
+// the default ruleset and variant.
+func (l *Lobby) Schedule(ctx context.Context, player *Player, rejoined bool) {
+	// the player may have left already, e.g. a reverse connection dropped
+	// after rejoining
+	driver := l.Driver(player)
+	if driver == nil {
+		return
+	}
+
+	infoCtx, cancel := context.WithTimeout(ctx, GetInfoTimeout)
+	defer cancel()
+
+	info, err := driver.Info(infoCtx)
+
+	ruleset, variant := core.BattleshipDefaultRuleset, core.BattleshipDefaultVariant
+
+	// players are updated by Join under the lock, so they're only read
+	// while holding it
+	l.mu.Lock()
+	player.Info = info
+	joined := player.String()
+	supported := player.Supports(ruleset, variant)
+	opponents := make([]*Player, 0)
+	skipped := make([]string, 0)
+
+	for _, p := range l.players {
+		if p == player || p.Driver == nil {
+			continue
+		}
+
+		if supported && p.Supports(ruleset, variant) {
+			opponents = append(opponents, p)
+		} else {
+			skipped = append(skipped, p.String())
+		}
+	}
+	l.mu.Unlock()
+
+	if err != nil {
+		l.logger.Warn(fmt.Sprintf("Failed to get info of %v: %v", joined, err))
+	}
+
+	if rejoined {
+		l.logger.Info(fmt.Sprintf("Player %v rejoined the lobby", joined))
+		return
+	}
+
+	l.logger.Info(fmt.Sprintf("Player %v joined the lobby", joined))
+
+	for _, opponent := range skipped {
+		l.logger.Info(fmt.Sprintf("Skipping games of %v and %v, %v/%v is not supported by both", opponent, joined, ruleset, variant))
+	}
+
+	for _, opponent := range opponents {
+		for i := 0; i < RoundsCount; i += 1 {
+			if i%2 == 0 {
+				l.runner.AddGame(opponent, player)
+			} else {
+				l.runner.AddGame(player, opponent)
+			}
+		}
+	}
+}
+
+// Leave disconnects the player, unless it has already rejoined with another driver.
+func (l *Lobby) Leave(player *Player, driver core.PlayerDriver) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if player.Driver == driver {
+		player.Driver = nil
+		l.logger.Info(fmt.Sprintf("Player %v left the lobby", player))
+	}
+}
+
+// Players returns joined players by id.
+func (l *Lobby) Players() map[string]*pbserver.PlayerProto {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	players := make(map[string]*pbserver.PlayerProto)
+
+	for _, p := range l.players {
+		players[p.Id] = p.ToProto()
+	}
+
+	return players
+}
+
+// Wait waits for running games to finish.
+func (l *Lobby) Wait() {
+	l.runner.Wait()
+}
+
+// Driver returns the current driver of the player, nil if it is disconnected.
+func (l *Lobby) Driver(player *Player) core.PlayerDriver {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return player.Driver
+}
+
+// GameDriver returns the driver to play a game of the player with, nil if
+// it is disconnected. Bots supporting the PlayGame stream play over it.
+func (l *Lobby) GameDriver(player *Player) core.PlayerDriver {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if d, ok := player.Driver.(*core.BotPlayerDriver); ok && player.HasCapability(core.BattleshipCapabilityPlayGameStream) {
+		return core.NewStreamingPlayerDriver(d)
+	}
+
+	return player.Driver
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestScheduleAfterLeaving(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	games := NewGameRepository()
+	lobby := NewLobby(context.Background(), games, logger)
+
+	if _, _, err := lobby.Join(RandomBotAddrPrefix+"opponent", "opponent", "", NewRandomPlayerDriver("opponent")); err != nil {
+		t.Fatal(err)
+	}
+
+	first, second := NewRandomPlayerDriver("bot"), NewRandomPlayerDriver("bot")
+
+	player, _, err := lobby.Join("first", "bot", "0123456789abcdef", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the second connection rejoins and drops before the first one is
+	// scheduled
+	if _, rejoined, err := lobby.Join("second", "bot", "0123456789abcdef", second); err != nil || !rejoined {
+		t.Fatalf("expected the bot to rejoin, got %v", err)
+	}
+
+	lobby.Leave(player, second)
+	lobby.Schedule(context.Background(), player, false)
+	lobby.Wait()
+
+	if n := len(games.FindAll()); n != 0 {
+		t.Fatalf("expected no games of a player that left, got %v", n)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+)
+
+// RandomBotAddrPrefix marks addresses of bots added by AddRandomBot, which
+// are played in process instead of being called over gRPC.
+const RandomBotAddrPrefix = "inprocess-bot://"
+
+// RandomPlayerDriver places the fleet in the same corner every game and
+// strikes at random, like the in-process bots of the Kotlin server.
+type RandomPlayerDriver struct {
+	name string
+}
+
+func NewRandomPlayerDriver(name string) *RandomPlayerDriver {
+	return &RandomPlayerDriver{name: name}
+}
+
+func (d *RandomPlayerDriver) Info(ctx context.Context) (*pbcore.BotInfoProto, error) {
+	info := &pbcore.BotInfoProto{}
+	info.Name = d.name
+	info.Author = "battleship-server"
+	info.Version = "1"
+	info.Rulesets = []string{core.BattleshipDefaultRuleset}
+	info.Variants = []string{core.BattleshipDefaultVariant}
+	info.ProtocolVersion = core.BattleshipProtocolVersion
+
+	return info, nil
+}
+
+func (d *RandomPlayerDriver) Field(ctx context.Context, gameId string) (core.BattleshipField, error) {
+	if err := sleep(ctx, 500*time.Millisecond); err != nil {
+		return core.NewBattleshipField(), err
+	}
+
+	f := core.NewBattleshipField()
+
+	for x, ship := range core.BattleshipKinds {
+		f.PlaceShip(ship, core.BattleshipPos{X: x, Y: 0}, false)
+	}
+
+	return f, nil
+}
+
+func (d *RandomPlayerDriver) Strike(ctx context.Context, gameId string, own, other core.BattleshipField) (core.BattleshipPos, error) {
+	if err := sleep(ctx, 10*time.Millisecond); err != nil {
+		return core.BattleshipPos{}, err
+	}
+
+	return core.BattleshipPos{X: rand.Intn(core.BattleshipFieldSize), Y: rand.Intn(core.BattleshipFieldSize)}, nil
+}
+
+func (d *RandomPlayerDriver) GameOver(ctx context.Context, gameId string, won bool, opponent *core.BattleshipField) {
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ReverseConnection is a client of a bot connected via the Connect stream,
+// sending bot requests over the stream and matching responses by request id.
+type ReverseConnection struct {
+	stream pbserver.BattleshipServerService_ConnectServer
+	logger *slog.Logger
+
+	sendMu sync.Mutex
+
+	mu      sync.Mutex
+	nextId  uint64
+	pending map[uint64]chan *pbserver.ConnectBotResponse
+	done    chan struct{}
+}
+
+func NewReverseConnection(stream pbserver.BattleshipServerService_ConnectServer, logger *slog.Logger) *ReverseConnection {
+	c := &ReverseConnection{}
+	c.stream = stream
+	c.logger = logger
+	c.pending = make(map[uint64]chan *pbserver.ConnectBotResponse)
+	c.done = make(chan struct{})
+
+	return c
+}
+
+// Serve receives bot responses until the stream is closed.
+func (c *ReverseConnection) Serve() error {
+	defer close(c.done)
+
+	for {
+		msg, err := c.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		response := msg.GetResponse()
+		if response == nil {
+			return status.Error(codes.InvalidArgument, "expected a response to a bot request")
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[response.RequestId]
+		delete(c.pending, response.RequestId)
+		c.mu.Unlock()
+
+		if !ok {
+			c.logger.Warn(fmt.Sprintf("received response to unknown or timed out request %v", response.RequestId))
+			continue
+		}
+
+		ch <- response
+	}
+}
+
+func (c *ReverseConnection) request(ctx context.Context, request *pbserver.ConnectResponse) (*pbserver.ConnectBotResponse, error) {
+	ch := make(chan *pbserver.ConnectBotResponse, 1)
+
+	c.mu.Lock()
+	c.nextId += 1
+	request.RequestId = c.nextId
+	c.pending[request.RequestId] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, request.RequestId)
+		c.mu.Unlock()
+	}()
+
+	c.sendMu.Lock()
+	err := c.stream.Send(request)
+	c.sendMu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case response := <-ch:
+		if e := response.GetError(); e != nil {
+			return nil, status.Error(codes.Code(e.Code), e.Message)
+		}
+
+		return response, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-c.done:
+		return nil, status.Error(codes.Unavailable, "bot has disconnected")
+	}
+}
+
+func (c *ReverseConnection) GetInfo(ctx context.Context, in *pbbot.GetInfoRequest, opts ...grpc.CallOption) (*pbbot.GetInfoResponse, error) {
+	response, err := c.request(ctx, &pbserver.ConnectResponse{Request: &pbserver.ConnectResponse_GetInfo{GetInfo: in}})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetGetInfo(), nil
+}
+
+func (c *ReverseConnection) GetField(ctx context.Context, in *pbbot.GetFieldRequest, opts ...grpc.CallOption) (*pbbot.GetFieldResponse, error) {
+	response, err := c.request(ctx, &pbserver.ConnectResponse{Request: &pbserver.ConnectResponse_GetField{GetField: in}})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetGetField(), nil
+}
+
+func (c *ReverseConnection) GetStrike(ctx context.Context, in *pbbot.GetStrikeRequest, opts ...grpc.CallOption) (*pbbot.GetStrikeResponse, error) {
+	response, err := c.request(ctx, &pbserver.ConnectResponse{Request: &pbserver.ConnectResponse_GetStrike{GetStrike: in}})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.GetGetStrike(), nil
+}
+
+func (c *ReverseConnection) PlayGame(ctx context.Context, opts ...grpc.CallOption) (pbbot.BattleshipBotService_PlayGameClient, error) {
+	return nil, status.Error(codes.Unimplemented, "PlayGame is not supported over reverse connections")
+}
+
+// ReverseAddr returns the address players connected via the Connect stream
+// are shown with.
+func ReverseAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return "reverse://" + p.Addr.String()
+	}
+
+	return "reverse://"
+}
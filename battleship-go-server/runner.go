@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+)
+
+// GameRunner plays scheduled games, every game in its own goroutine.
+type GameRunner struct {
+	logger *slog.Logger
+	// lobby resolves current drivers, as players could have reconnected
+	// since the game was scheduled
+	lobby *Lobby
+	games *GameRepository
+
+	ctx context.Context
+	wg  sync.WaitGroup
+}
+
+func NewGameRunner(ctx context.Context, lobby *Lobby, games *GameRepository, logger *slog.Logger) *GameRunner {
+	r := &GameRunner{}
+	r.ctx = ctx
+	r.lobby = lobby
+	r.games = games
+	r.logger = logger
+
+	return r
+}
+
+func (r *GameRunner) AddGame(player1, player2 *Player) {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+		r.runGame(player1, player2)
+	}()
+}
+
+// Wait waits for running games to finish.
+func (r *GameRunner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *GameRunner) runGame(player1, player2 *Player) {
+	dbGame := r.games.Create(player1.Id, player2.Id)
+	game := core.NewBattleshipGame(dbGame.Id, player1.Id, player2.Id)
+
+	dbGame.State = pbserver.GameStateProto_RUNNING
+	r.games.Update(dbGame)
+	r.logger.Info(fmt.Sprintf("Game %v started: %v vs %v", game.Id, player1, player2))
+
+	err := r.playGame(game, player1, player2)
+
+	if err != nil && !errors.Is(err, core.ErrGameTooLong) {
+		r.logger.Error(fmt.Sprintf("Game %v failed during game loop: %v", game.Id, err))
+	}
+
+	if game.IsOver() {
+		r.logger.Info(fmt.Sprintf("Game %v finished: %v vs %v", game.Id, player1, player2))
+	} else {
+		r.logger.Info(fmt.Sprintf("Game %v was taking too long, aborted", game.Id))
+	}
+
+	dbGame.State = pbserver.GameStateProto_FINISHED
+	dbGame.Log = game.Log
+	r.games.Update(dbGame)
+}
+
+func (r *GameRunner) playGame(game *core.BattleshipGame, player1, player2 *Player) error {
+	players := make(map[string]core.PlayerDriver)
+	errs := make([]error, 0)
+
+	opponents := map[*Player]*Player{player1: player2, player2: player1}
+
+	for _, p := range []*Player{player1, player2} {
+		driver := r.lobby.GameDriver(p)
+		if driver == nil {
+			errs = append(errs, fmt.Errorf("%v is not connected", p))
+			continue
+		}
+
+		players[p.Id] = core.NewOpponentNamedDriver(driver, opponents[p].Name)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		game.AppendError(err)
+		game.AppendError(core.ErrGameTooLong)
+		return err
+	}
+
+	return core.PlayBattleshipGame(r.ctx, game, players)
+}
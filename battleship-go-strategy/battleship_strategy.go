@@ -0,0 +1,50 @@
+// Package strategy contains built-in placement and targeting strategies.
+// Importing it registers them in the battleship-go-core strategy registry.
+package strategy
+
+import (
+	"math/rand"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+func init() {
+	core.RegisterPlacementStrategy("random", func() core.BattleshipPlacementStrategy { return RandomPlacement{} })
+	core.RegisterPlacementStrategy("spaced", func() core.BattleshipPlacementStrategy {
+		s := NewSpacedPlacement()
+		return &s
+	})
+	core.RegisterPlacementStrategy("spread", func() core.BattleshipPlacementStrategy {
+		s := NewSpreadPlacement()
+		return &s
+	})
+	core.RegisterPlacementStrategy("adversarial", func() core.BattleshipPlacementStrategy {
+		a := NewAdversarialPlacement()
+		return &a
+	})
+
+	core.RegisterTargetingStrategy("random", func() core.BattleshipTargetingStrategy { return RandomTargeting{} })
+	core.RegisterTargetingStrategy("hunt", func() core.BattleshipTargetingStrategy {
+		h := NewHuntTargeting()
+		return &h
+	})
+	core.RegisterTargetingStrategy("density", func() core.BattleshipTargetingStrategy {
+		d := NewDensityTargeting()
+		return &d
+	})
+}
+
+// Directions are the four orthogonal neighbour offsets.
+var Directions = []core.BattleshipPos{{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1}}
+
+func add(a, b core.BattleshipPos) core.BattleshipPos {
+	return core.BattleshipPos{X: a.X + b.X, Y: a.Y + b.Y}
+}
+
+func pick(ps []core.BattleshipPos, rng *rand.Rand) (core.BattleshipPos, error) {
+	if len(ps) == 0 {
+		return core.BattleshipPos{}, core.ErrNoPositionsLeft
+	}
+
+	return ps[rng.Intn(len(ps))], nil
+}
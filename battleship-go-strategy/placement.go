@@ -0,0 +1,339 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	"golang.org/x/exp/maps"
+)
+
+// RandomPlacement puts every ship at a uniformly random free position and orientation.
+type RandomPlacement struct{}
+
+func (RandomPlacement) Place(ctx context.Context, rng *rand.Rand) (core.BattleshipField, error) {
+	f := core.NewBattleshipField()
+
+	for _, ship := range core.BattleshipKinds {
+		for {
+			if err := ctx.Err(); err != nil {
+				return f, err
+			}
+
+			if ok := tryToAddShip(&f, ship, rng); ok {
+				break
+			}
+		}
+	}
+
+	return f, nil
+}
+
+func tryToAddShip(f *core.BattleshipField, ship core.BattleshipKind, rng *rand.Rand) bool {
+	shipSize := ship.Size()
+	horizontal := rng.Intn(2) == 0
+
+	x := rng.Intn(core.BattleshipFieldSize)
+	y := rng.Intn(core.BattleshipFieldSize)
+
+	if horizontal {
+		x = min(x, core.BattleshipFieldSize-shipSize)
+	} else {
+		y = min(y, core.BattleshipFieldSize-shipSize)
+	}
+
+	return f.PlaceShip(ship, core.BattleshipPos{X: x, Y: y}, horizontal)
+}
+
+// SpacedPlacement puts every ship at a position chosen uniformly among all
+// positions fitting it, keeping ships at least Spacing free tiles apart,
+// diagonals included. Unlike RandomPlacement, it isn't biased towards edges.
+type SpacedPlacement struct {
+	Spacing int
+}
+
+func NewSpacedPlacement() SpacedPlacement {
+	return SpacedPlacement{Spacing: 1}
+}
+
+func (s SpacedPlacement) Place(ctx context.Context, rng *rand.Rand) (core.BattleshipField, error) {
+	return placeSpaced(ctx, s.Spacing, rng)
+}
+
+func (s *SpacedPlacement) Params() []core.BattleshipStrategyParam {
+	return []core.BattleshipStrategyParam{
+		{Name: "spacing", Value: float64(s.Spacing), Min: 0, Max: 2, Step: 1, Integer: true},
+	}
+}
+
+func (s *SpacedPlacement) SetParam(name string, value float64) error {
+	switch name {
+	case "spacing":
+		s.Spacing = int(value)
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	return nil
+}
+
+// SpreadPlacement places Candidates spaced fleets and keeps the one with
+// ships furthest apart, so finding a ship tells the opponent the least
+// about where the others are.
+type SpreadPlacement struct {
+	Candidates int
+}
+
+func NewSpreadPlacement() SpreadPlacement {
+	return SpreadPlacement{Candidates: 16}
+}
+
+func (s SpreadPlacement) Place(ctx context.Context, rng *rand.Rand) (core.BattleshipField, error) {
+	var best core.BattleshipField
+	bestMin, bestSum := -1, -1
+
+	for i := 0; i < s.Candidates; i += 1 {
+		f, err := placeSpaced(ctx, 1, rng)
+		if err != nil {
+			return f, err
+		}
+
+		minGap, sumGap := shipGaps(&f)
+
+		if minGap > bestMin || (minGap == bestMin && sumGap > bestSum) {
+			best, bestMin, bestSum = f, minGap, sumGap
+		}
+	}
+
+	return best, nil
+}
+
+func (s *SpreadPlacement) Params() []core.BattleshipStrategyParam {
+	return []core.BattleshipStrategyParam{
+		{Name: "candidates", Value: float64(s.Candidates), Min: 1, Max: 64, Step: 4, Integer: true},
+	}
+}
+
+func (s *SpreadPlacement) SetParam(name string, value float64) error {
+	switch name {
+	case "candidates":
+		s.Candidates = int(value)
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	return nil
+}
+
+// AdversarialPlacement places Candidates spaced fleets and keeps the one a
+// simulated DensityTargeting shooter needs the most shots to sink, averaged
+// over Simulations games.
+type AdversarialPlacement struct {
+	Candidates  int
+	Simulations int
+}
+
+func NewAdversarialPlacement() AdversarialPlacement {
+	return AdversarialPlacement{Candidates: 8, Simulations: 1}
+}
+
+func (a AdversarialPlacement) Place(ctx context.Context, rng *rand.Rand) (core.BattleshipField, error) {
+	shooter := NewDensityTargeting()
+
+	var best core.BattleshipField
+	bestShots := -1
+
+	for i := 0; i < a.Candidates; i += 1 {
+		f, err := placeSpaced(ctx, 1, rng)
+		if err != nil {
+			return f, err
+		}
+
+		shots := 0
+		for j := 0; j < a.Simulations; j += 1 {
+			n, err := ShotsToSink(ctx, f, shooter, rng)
+			if err != nil {
+				return f, err
+			}
+
+			shots += n
+		}
+
+		if shots > bestShots {
+			best, bestShots = f, shots
+		}
+	}
+
+	return best, nil
+}
+
+func (a *AdversarialPlacement) Params() []core.BattleshipStrategyParam {
+	return []core.BattleshipStrategyParam{
+		{Name: "candidates", Value: float64(a.Candidates), Min: 1, Max: 32, Step: 2, Integer: true},
+		{Name: "simulations", Value: float64(a.Simulations), Min: 1, Max: 8, Step: 1, Integer: true},
+	}
+}
+
+func (a *AdversarialPlacement) SetParam(name string, value float64) error {
+	switch name {
+	case "candidates":
+		a.Candidates = int(value)
+	case "simulations":
+		a.Simulations = int(value)
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	return nil
+}
+
+// ShotsToSink returns the number of shots the targeting strategy needs to
+// sink the fleet of the field, hits and misses of the field are ignored.
+func ShotsToSink(ctx context.Context, f core.BattleshipField, targeting core.BattleshipTargetingStrategy, rng *rand.Rand) (int, error) {
+	view := core.NewBattleshipOpponentView()
+	hits := 0
+
+	for shots := 1; shots <= core.BattleshipFieldSize*core.BattleshipFieldSize; shots += 1 {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		pos, err := targeting.Target(ctx, &view, rng)
+		if err != nil {
+			return 0, err
+		}
+
+		hit := pos.IsInBounds() && !f.Field[pos.Y][pos.X].IsEmpty()
+		if hit && view.IsUnknown(pos) {
+			hits += 1
+		}
+
+		view.Mark(pos, hit)
+
+		if hits == core.BattleshipTilesToHitCount {
+			return shots, nil
+		}
+	}
+
+	return 0, core.ErrNoPositionsLeft
+}
+
+// maxSpacedAttempts is the number of times a spaced fleet is started over
+// before the spacing is reduced, as large spacings don't always fit.
+const maxSpacedAttempts = 100
+
+func placeSpaced(ctx context.Context, spacing int, rng *rand.Rand) (core.BattleshipField, error) {
+	for {
+		for attempt := 0; attempt < maxSpacedAttempts; attempt += 1 {
+			if err := ctx.Err(); err != nil {
+				return core.NewBattleshipField(), err
+			}
+
+			if f, ok := tryToPlaceSpaced(spacing, rng); ok {
+				return f, nil
+			}
+		}
+
+		if spacing == 0 {
+			return core.NewBattleshipField(), errors.New("failed to place fleet")
+		}
+
+		spacing -= 1
+	}
+}
+
+// tryToPlaceSpaced places ships from the largest one, which is the hardest
+// to fit, failing if a ship has no position left.
+func tryToPlaceSpaced(spacing int, rng *rand.Rand) (core.BattleshipField, bool) {
+	f := core.NewBattleshipField()
+
+	ships := slices.Clone(core.BattleshipKinds)
+	slices.SortStableFunc(ships, func(a, b core.BattleshipKind) int { return b.Size() - a.Size() })
+
+	for _, ship := range ships {
+		type placement struct {
+			pos        core.BattleshipPos
+			horizontal bool
+		}
+
+		candidates := make([]placement, 0)
+
+		for y := 0; y < core.BattleshipFieldSize; y += 1 {
+			for x := 0; x < core.BattleshipFieldSize; x += 1 {
+				for _, horizontal := range []bool{true, false} {
+					pos := core.BattleshipPos{X: x, Y: y}
+
+					if f.CanPlaceShip(ship, pos, horizontal) && isClear(&f, core.ShipPositions(ship, pos, horizontal), spacing) {
+						candidates = append(candidates, placement{pos, horizontal})
+					}
+				}
+			}
+		}
+
+		if len(candidates) == 0 {
+			return f, false
+		}
+
+		c := candidates[rng.Intn(len(candidates))]
+		f.PlaceShip(ship, c.pos, c.horizontal)
+	}
+
+	return f, true
+}
+
+// isClear reports whether no ship is within spacing tiles of the positions.
+func isClear(f *core.BattleshipField, ps []core.BattleshipPos, spacing int) bool {
+	for _, p := range ps {
+		for dy := -spacing; dy <= spacing; dy += 1 {
+			for dx := -spacing; dx <= spacing; dx += 1 {
+				n := core.BattleshipPos{X: p.X + dx, Y: p.Y + dy}
+
+				if n.IsInBounds() && !f.Field[n.Y][n.X].IsEmpty() {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// shipGaps returns the smallest and the total distance between every two
+// ships, the distance being the number of moves of a chess king between
+// their closest tiles.
+func shipGaps(f *core.BattleshipField) (int, int) {
+	ships := f.Ships()
+	kinds := maps.Keys(ships)
+	slices.Sort(kinds)
+
+	minGap, sumGap := math.MaxInt, 0
+
+	for i := 0; i < len(kinds); i += 1 {
+		for j := i + 1; j < len(kinds); j += 1 {
+			gap := math.MaxInt
+
+			for _, a := range ships[kinds[i]] {
+				for _, b := range ships[kinds[j]] {
+					gap = min(gap, max(abs(a.X-b.X), abs(a.Y-b.Y)))
+				}
+			}
+
+			minGap = min(minGap, gap)
+			sumGap += gap
+		}
+	}
+
+	return minGap, sumGap
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
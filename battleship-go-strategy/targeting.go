@@ -0,0 +1,230 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// RandomTargeting strikes a uniformly random unknown position.
+type RandomTargeting struct{}
+
+func (RandomTargeting) Target(ctx context.Context, view *core.BattleshipOpponentView, rng *rand.Rand) (core.BattleshipPos, error) {
+	return pick(view.Unknown(), rng)
+}
+
+// HuntTargeting strikes random positions of a checkerboard parity until
+// something is hit, then strikes around the hits, preferring to continue a
+// line of hits.
+type HuntTargeting struct {
+	Parity int
+}
+
+func NewHuntTargeting() HuntTargeting {
+	return HuntTargeting{Parity: 0}
+}
+
+func (h *HuntTargeting) Params() []core.BattleshipStrategyParam {
+	return []core.BattleshipStrategyParam{
+		{Name: "parity", Value: float64(h.Parity), Min: 0, Max: 1, Step: 1, Integer: true},
+	}
+}
+
+func (h *HuntTargeting) SetParam(name string, value float64) error {
+	switch name {
+	case "parity":
+		h.Parity = int(value)
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	return nil
+}
+
+func (h HuntTargeting) Target(ctx context.Context, view *core.BattleshipOpponentView, rng *rand.Rand) (core.BattleshipPos, error) {
+	line, adjacent := TargetCandidates(view)
+
+	if len(line) > 0 {
+		return pick(line, rng)
+	}
+
+	if len(adjacent) > 0 {
+		return pick(adjacent, rng)
+	}
+
+	unknown := view.Unknown()
+	hunt := make([]core.BattleshipPos, 0, len(unknown))
+
+	for _, pos := range unknown {
+		if (pos.X+pos.Y)%2 == h.Parity {
+			hunt = append(hunt, pos)
+		}
+	}
+
+	if len(hunt) > 0 {
+		return pick(hunt, rng)
+	}
+
+	return pick(unknown, rng)
+}
+
+// TargetCandidates returns unknown positions continuing a line of two or
+// more hits, and unknown positions adjacent to any hit.
+func TargetCandidates(view *core.BattleshipOpponentView) ([]core.BattleshipPos, []core.BattleshipPos) {
+	line := make([]core.BattleshipPos, 0)
+	adjacent := make([]core.BattleshipPos, 0)
+	seenLine := make(map[core.BattleshipPos]bool)
+	seenAdjacent := make(map[core.BattleshipPos]bool)
+
+	for y, l := range view.Tiles {
+		for x, t := range l {
+			if t != core.BattleshipOpponentTileHit {
+				continue
+			}
+
+			hit := core.BattleshipPos{X: x, Y: y}
+
+			for _, d := range Directions {
+				next := add(hit, d)
+
+				if view.IsUnknown(next) && !seenAdjacent[next] {
+					seenAdjacent[next] = true
+					adjacent = append(adjacent, next)
+				}
+
+				back := core.BattleshipPos{X: hit.X - d.X, Y: hit.Y - d.Y}
+				if !back.IsInBounds() || view.Tile(back) != core.BattleshipOpponentTileHit {
+					continue
+				}
+
+				// hit continues a line coming from the back direction
+				if view.IsUnknown(next) && !seenLine[next] {
+					seenLine[next] = true
+					line = append(line, next)
+				}
+			}
+		}
+	}
+
+	return line, adjacent
+}
+
+// DensityTargeting strikes the unknown position covered by the largest
+// number of possible ship placements. Placements going through hits are
+// weighted higher, so the strategy finishes off ships it has found. Density
+// of edge positions is multiplied by EdgeBias, to favor or avoid them.
+type DensityTargeting struct {
+	HitWeight float64
+	EdgeBias  float64
+}
+
+func NewDensityTargeting() DensityTargeting {
+	return DensityTargeting{HitWeight: 20, EdgeBias: 1}
+}
+
+func (d *DensityTargeting) Params() []core.BattleshipStrategyParam {
+	return []core.BattleshipStrategyParam{
+		{Name: "hit_weight", Value: d.HitWeight, Min: 1, Max: 100, Step: 5},
+		{Name: "edge_bias", Value: d.EdgeBias, Min: 0.5, Max: 2, Step: 0.1},
+	}
+}
+
+func (d *DensityTargeting) SetParam(name string, value float64) error {
+	switch name {
+	case "hit_weight":
+		d.HitWeight = value
+	case "edge_bias":
+		d.EdgeBias = value
+	default:
+		return fmt.Errorf("unknown parameter %q", name)
+	}
+
+	return nil
+}
+
+func (d DensityTargeting) Target(ctx context.Context, view *core.BattleshipOpponentView, rng *rand.Rand) (core.BattleshipPos, error) {
+	density := d.Density(view)
+
+	best := make([]core.BattleshipPos, 0)
+	bestDensity := -1.0
+
+	for _, pos := range view.Unknown() {
+		cur := density[pos.Y][pos.X]
+
+		if cur > bestDensity {
+			best = best[:0]
+			bestDensity = cur
+		}
+
+		if cur == bestDensity {
+			best = append(best, pos)
+		}
+	}
+
+	return pick(best, rng)
+}
+
+// Density returns the weighted number of possible ship placements covering every unknown position.
+func (d DensityTargeting) Density(view *core.BattleshipOpponentView) [core.BattleshipFieldSize][core.BattleshipFieldSize]float64 {
+	density := [core.BattleshipFieldSize][core.BattleshipFieldSize]float64{}
+
+	for _, ship := range core.BattleshipKinds {
+		for y := 0; y < core.BattleshipFieldSize; y += 1 {
+			for x := 0; x < core.BattleshipFieldSize; x += 1 {
+				for _, horizontal := range []bool{true, false} {
+					ps := core.ShipPositions(ship, core.BattleshipPos{X: x, Y: y}, horizontal)
+
+					hits, ok := coveredHits(view, ps)
+					if !ok || hits == len(ps) {
+						continue
+					}
+
+					weight := 1.0
+					for i := 0; i < hits; i += 1 {
+						weight *= d.HitWeight
+					}
+
+					for _, p := range ps {
+						if view.Tile(p) == core.BattleshipOpponentTileUnknown {
+							density[p.Y][p.X] += weight
+						}
+					}
+				}
+			}
+		}
+	}
+
+	last := core.BattleshipFieldSize - 1
+	for y := 0; y < core.BattleshipFieldSize; y += 1 {
+		for x := 0; x < core.BattleshipFieldSize; x += 1 {
+			if x == 0 || y == 0 || x == last || y == last {
+				density[y][x] *= d.EdgeBias
+			}
+		}
+	}
+
+	return density
+}
+
+// coveredHits returns the number of hits covered by positions, or false if
+// the positions can't be a ship because they are out of bounds or were missed.
+func coveredHits(view *core.BattleshipOpponentView, ps []core.BattleshipPos) (int, bool) {
+	hits := 0
+
+	for _, p := range ps {
+		if !p.IsInBounds() {
+			return 0, false
+		}
+
+		switch view.Tile(p) {
+		case core.BattleshipOpponentTileMiss:
+			return 0, false
+		case core.BattleshipOpponentTileHit:
+			hits += 1
+		}
+	}
+
+	return hits, true
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ConfigEnv names the environment variable pointing to the referee config file.
+const ConfigEnv = "BATTLESHIP_REFEREE_CONFIG"
+
+// GetInfoTimeout is the timeout of GetInfo requests, made before the game
+// starts, so they aren't limited by the move timeout.
+const GetInfoTimeout = 5 * time.Second
+
+type Config struct {
+	Bot1Addr    string        `yaml:"bot1_addr" toml:"bot1_addr" env:"BATTLESHIP_REFEREE_BOT1_ADDR" flag:"bot1-addr" usage:"address of the bot placing its field and striking first"`
+	Bot1Secret  string        `yaml:"bot1_secret" toml:"bot1_secret" env:"BATTLESHIP_REFEREE_BOT1_SECRET" flag:"bot1-secret" usage:"secret to sign requests to the first bot with" secret:"true"`
+	Bot2Addr    string        `yaml:"bot2_addr" toml:"bot2_addr" env:"BATTLESHIP_REFEREE_BOT2_ADDR" flag:"bot2-addr" usage:"address of the second bot"`
+	Bot2Secret  string        `yaml:"bot2_secret" toml:"bot2_secret" env:"BATTLESHIP_REFEREE_BOT2_SECRET" flag:"bot2-secret" usage:"secret to sign requests to the second bot with" secret:"true"`
+	MoveTimeout time.Duration `yaml:"move_timeout" toml:"move_timeout" env:"BATTLESHIP_REFEREE_MOVE_TIMEOUT" flag:"move-timeout" usage:"time a bot has to place its field or make a strike"`
+	Format      string        `yaml:"format" toml:"format" env:"BATTLESHIP_REFEREE_FORMAT" flag:"format" usage:"output format: summary, json or binary (GameProto)"`
+	Output      string        `yaml:"output" toml:"output" env:"BATTLESHIP_REFEREE_OUTPUT" flag:"output" usage:"file to write the output to, stdout if empty"`
+	TLS         bool          `yaml:"tls" toml:"tls" env:"BATTLESHIP_REFEREE_TLS" flag:"tls" usage:"connect to bots over TLS"`
+	TLSCAFile   string        `yaml:"tls_ca_file" toml:"tls_ca_file" env:"BATTLESHIP_REFEREE_TLS_CA_FILE" flag:"tls-ca-file" usage:"CA certificates to verify bot certificates with, instead of system ones"`
+	TLSCertFile string        `yaml:"tls_cert_file" toml:"tls_cert_file" env:"BATTLESHIP_REFEREE_TLS_CERT_FILE" flag:"tls-cert-file" usage:"client certificate presented to bots requiring mutual TLS"`
+	TLSKeyFile  string        `yaml:"tls_key_file" toml:"tls_key_file" env:"BATTLESHIP_REFEREE_TLS_KEY_FILE" flag:"tls-key-file" usage:"client certificate key"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.MoveTimeout = 10 * time.Second
+	c.Format = "summary"
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if c.Bot1Addr == "" {
+		errs = append(errs, errors.New("bot1_addr must not be empty"))
+	}
+
+	if c.Bot2Addr == "" {
+		errs = append(errs, errors.New("bot2_addr must not be empty"))
+	}
+
+	if c.MoveTimeout <= 0 {
+		errs = append(errs, errors.New("move_timeout must be positive"))
+	}
+
+	if c.Format != "summary" && c.Format != "json" && c.Format != "binary" {
+		errs = append(errs, fmt.Errorf("format: unknown format %q, expected summary, json or binary", c.Format))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("tls_cert_file and tls_key_file must be set together"))
+	}
+
+	if !c.TLS && (c.TLSCAFile != "" || c.TLSCertFile != "") {
+		errs = append(errs, errors.New("tls_ca_file, tls_cert_file and tls_key_file require tls"))
+	}
+
+	if _, err := c.DialOptions(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DialOptions returns options of connections to bots.
+func (c Config) DialOptions() ([]grpc.DialOption, error) {
+	if !c.TLS {
+		return []grpc.DialOption{}, nil
+	}
+
+	config, err := core.NewClientTLSConfig(c.TLSCAFile, c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}, nil
+}
+
+// Player is a bot taking part in the match.
+type Player struct {
+	Id     string
+	Addr   string
+	Info   *pbcore.BotInfoProto
+	Driver *core.BotPlayerDriver
+	close  func() error
+}
+
+func (p *Player) Name() string {
+	if p.Info != nil && p.Info.Name != "" {
+		return p.Info.Name
+	}
+
+	return p.Addr
+}
+
+func (p *Player) ToProto() *pbserver.PlayerProto {
+	return &pbserver.PlayerProto{Id: p.Id, Name: p.Name(), Info: p.Info}
+}
+
+func NewPlayer(ctx context.Context, addr, secret string, timeout time.Duration, opts []grpc.DialOption) (*Player, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v: %w", addr, err)
+	}
+
+	client := pbbot.NewBattleshipBotServiceClient(conn)
+
+	info, err := core.NewBotPlayerDriver(client, secret, GetInfoTimeout).Info(ctx)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to get info of %v: %w", addr, err)
+	}
+
+	p := &Player{}
+	p.Id = core.NewId()
+	p.Addr = addr
+	p.Info = info
+	p.Driver = core.NewBotPlayerDriver(client, secret, timeout)
+	p.close = conn.Close
+
+	return p, nil
+}
+
+func (p *Player) Close() error {
+	return p.close()
+}
+
+// Match is a game played by the referee and its outcome.
+type Match struct {
+	Game    *core.BattleshipGame
+	Player1 *Player
+	Player2 *Player
+	Err     error
+}
+
+// PlayMatch plays a game between the players, player 1 strikes first.
+func PlayMatch(ctx context.Context, player1, player2 *Player) Match {
+	m := Match{}
+	m.Game = core.NewBattleshipGame(core.NewId(), player1.Id, player2.Id)
+	m.Player1 = player1
+	m.Player2 = player2
+
+	players := map[string]core.PlayerDriver{
+		player1.Id: core.NewOpponentNamedDriver(player1.Driver, player2.Name()),
+		player2.Id: core.NewOpponentNamedDriver(player2.Driver, player1.Name()),
+	}
+
+	m.Err = core.PlayBattleshipGame(ctx, m.Game, players)
+
+	return m
+}
+
+func (m Match) ToProto() *pbserver.GameProto {
+	game := &pbserver.GameProto{}
+	game.Id = m.Game.Id
+	game.Player_1 = m.Player1.ToProto()
+	game.Player_2 = m.Player2.ToProto()
+	game.State = pbserver.GameStateProto_FINISHED
+	game.Log = m.Game.LogToProto()
+
+	return game
+}
+
+func (m Match) player(id string) *Player {
+	if id == m.Player1.Id {
+		return m.Player1
+	}
+
+	return m.Player2
+}
+
+func WriteSummary(w io.Writer, m Match) error {
+	shots := make(map[string]int)
+	hits := make(map[string]int)
+
+	for _, e := range m.Game.Log {
+		if e.Strike == nil {
+			continue
+		}
+
+		shots[e.Strike.AttackerId] += 1
+
+		other := m.Game.PlayerField(m.Game.OtherPlayerId(e.Strike.AttackerId))
+		if other != nil && e.Strike.Pos.IsInBounds() && !other.Field[e.Strike.Pos.Y][e.Strike.Pos.X].IsEmpty() {
+			hits[e.Strike.AttackerId] += 1
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("Game %v", m.Game.Id),
+		fmt.Sprintf("Player 1: %v (%v)", m.Player1.Name(), m.Player1.Addr),
+		fmt.Sprintf("Player 2: %v (%v)", m.Player2.Name(), m.Player2.Addr),
+	}
+
+	for _, p := range []*Player{m.Player1, m.Player2} {
+		lines = append(lines, fmt.Sprintf("%v: %v shots, %v hits", p.Name(), shots[p.Id], hits[p.Id]))
+	}
+
+	if winnerId, ok := m.Game.WinnerId(); ok {
+		lines = append(lines, fmt.Sprintf("Winner: %v", m.player(winnerId).Name()))
+	} else {
+		// the game state isn't advanced by a failed move
+		lines = append(lines, fmt.Sprintf("Aborted on %v's move: %v", m.player(m.Game.State.PlayerId).Name(), m.Err))
+	}
+
+	for _, l := range lines {
+		if _, err := fmt.Fprintln(w, l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func WriteMatch(w io.Writer, m Match, format string) error {
+	switch format {
+	case "json":
+		b, err := protojson.MarshalOptions{Multiline: true}.Marshal(m.ToProto())
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(append(b, '\n'))
+		return err
+	case "binary":
+		b, err := proto.Marshal(m.ToProto())
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(b)
+		return err
+	default:
+		return WriteSummary(w, m)
+	}
+}
+
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	opts, err := config.DialOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure connections: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	player1, err := NewPlayer(ctx, config.Bot1Addr, config.Bot1Secret, config.MoveTimeout, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer player1.Close()
+
+	player2, err := NewPlayer(ctx, config.Bot2Addr, config.Bot2Secret, config.MoveTimeout, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer player2.Close()
+
+	m := PlayMatch(ctx, player1, player2)
+
+	out := io.Writer(os.Stdout)
+	if config.Output != "" {
+		f, err := os.Create(config.Output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create output file: %v\n", err)
+			os.Exit(2)
+		}
+		defer f.Close()
+
+		out = f
+	}
+
+	if err := WriteMatch(out, m, config.Format); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write output: %v\n", err)
+		os.Exit(2)
+	}
+
+	if m.Err != nil {
+		fmt.Fprintf(os.Stderr, "game %v aborted: %v\n", m.Game.Id, m.Err)
+		os.Exit(1)
+	}
+}
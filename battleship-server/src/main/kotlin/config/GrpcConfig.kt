@@ -5,9 +5,21 @@ import org.springframework.boot.context.properties.ConfigurationProperties
 @ConfigurationProperties(prefix = "grpc")
 data class GrpcConfig(
     val server: GrpcServer,
+    val bots: GrpcBots,
 ) {
     data class GrpcServer(
         val port: Int,
         val host: String,
     )
+
+    data class GrpcBots(
+        val tls: GrpcBotsTls,
+    )
+
+    data class GrpcBotsTls(
+        val enabled: Boolean,
+        val caFile: String,
+        val certFile: String,
+        val keyFile: String,
+    )
 }
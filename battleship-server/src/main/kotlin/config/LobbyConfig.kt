@@ -0,0 +1,8 @@
+package dev.spris.battleship.server.config
+
+import org.springframework.boot.context.properties.ConfigurationProperties
+
+@ConfigurationProperties(prefix = "lobby")
+data class LobbyConfig(
+    val requireSecret: Boolean,
+)
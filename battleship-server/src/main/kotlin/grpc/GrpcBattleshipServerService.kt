@@ -5,11 +5,13 @@ import dev.spris.battleship.core.BattleshipGameId
 import dev.spris.battleship.core.BattleshipGameLogActionEntry
 import dev.spris.battleship.proto.server.v1.*
 import dev.spris.battleship.proto.server.v1.BattleshipServerServiceGrpcKt.BattleshipServerServiceCoroutineImplBase
+import dev.spris.battleship.server.config.LobbyConfig
 import dev.spris.battleship.server.repository.Game
 import dev.spris.battleship.server.repository.GameRepository
 import dev.spris.battleship.server.repository.GameState
 import dev.spris.battleship.server.repository.PlayerRepository
 import dev.spris.battleship.server.service.GameLobby
+import dev.spris.battleship.server.service.MIN_SECRET_LENGTH
 import io.github.oshai.kotlinlogging.KotlinLogging
 import java.util.concurrent.atomic.AtomicInteger
 import org.springframework.stereotype.Component
@@ -19,15 +21,23 @@ private val logger = KotlinLogging.logger {}
 @Component
 class GrpcBattleshipServerService(
     private val gameLobby: GameLobby,
+    private val lobbyConfig: LobbyConfig,
     private val gameRepository: GameRepository,
     private val playerRepository: PlayerRepository,
 ) : BattleshipServerServiceCoroutineImplBase() {
     private var randomBotCounter = AtomicInteger(0)
 
     override suspend fun joinLobby(request: JoinLobbyRequest): JoinLobbyResponse {
-        logger.info { "joinLobby: $request" }
+        logger.info { "joinLobby: addr=${request.addr} name=${request.name}" }
 
-        gameLobby.join(request.addr, request.name)
+        require(request.secret.isNotEmpty() || !lobbyConfig.requireSecret) {
+            "Joining the lobby requires a secret"
+        }
+        require(request.secret.isEmpty() || request.secret.length >= MIN_SECRET_LENGTH) {
+            "Secret must be at least $MIN_SECRET_LENGTH characters long"
+        }
+
+        gameLobby.join(request.addr, request.name, request.secret)
 
         return joinLobbyResponse {}
     }
@@ -6,6 +6,7 @@ import dev.spris.battleship.proto.core.v1.*
 import dev.spris.battleship.proto.server.v1.*
 import dev.spris.battleship.proto.server.v1.gameLogEntryProto
 import dev.spris.battleship.proto.server.v1.playerProto
+import dev.spris.battleship.server.repository.BotInfo
 import dev.spris.battleship.server.repository.GameState
 import dev.spris.battleship.server.repository.Player
 
@@ -19,8 +20,31 @@ fun GameState.toProto() =
 fun Player.toProto() = playerProto {
     id = this@toProto.id.id
     name = this@toProto.name
+
+    this@toProto.info?.let { info = it.toProto() }
+}
+
+fun BotInfo.toProto() = botInfoProto {
+    name = this@toProto.name
+    author = this@toProto.author
+    version = this@toProto.version
+    rulesets.addAll(this@toProto.rulesets)
+    variants.addAll(this@toProto.variants)
+    protocolVersion = this@toProto.protocolVersion
+    capabilities.addAll(this@toProto.capabilities)
 }
 
+fun BotInfoProto.toDomain() =
+    BotInfo(
+        name = this.name,
+        author = this.author,
+        version = this.version,
+        rulesets = this.rulesetsList.toList(),
+        variants = this.variantsList.toList(),
+        protocolVersion = this.protocolVersion,
+        capabilities = this.capabilitiesList.toList(),
+    )
+
 fun BattleshipGameLogEntry.toProto() = gameLogEntryProto {
     when (val entry = this@toProto) {
         is BattleshipGameLogActionEntry ->
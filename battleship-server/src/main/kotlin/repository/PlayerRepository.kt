@@ -5,18 +5,54 @@ import dev.spris.battleship.server.service.IdGenerator
 import java.util.concurrent.ConcurrentHashMap
 import org.springframework.stereotype.Repository
 
+const val PROTOCOL_VERSION = 1
+const val DEFAULT_RULESET = "classic"
+const val DEFAULT_VARIANT = "standard"
+const val PLAY_GAME_STREAM_CAPABILITY = "play_game_stream"
+
 data class Player(
     val id: BattleshipPlayerId,
     val addr: String,
     val name: String,
+    val secret: String = "",
+    val info: BotInfo? = null,
+) {
+    override fun toString() = "Player(id=$id, addr=$addr, name=$name)"
+
+    /** Bots not implementing GetInfo are assumed to support only the default ruleset and variant. */
+    fun supports(
+        ruleset: String,
+        variant: String,
+    ): Boolean {
+        if (info == null) {
+            return ruleset == DEFAULT_RULESET && variant == DEFAULT_VARIANT
+        }
+
+        return ruleset in info.rulesets && variant in info.variants
+    }
+
+    fun hasCapability(capability: String): Boolean = info?.capabilities?.contains(capability) == true
+}
+
+data class BotInfo(
+    val name: String,
+    val author: String,
+    val version: String,
+    val rulesets: List<String>,
+    val variants: List<String>,
+    val protocolVersion: Int,
+    val capabilities: List<String> = emptyList(),
 )
 
 interface PlayerRepository {
     suspend fun create(
         addr: String,
         name: String,
+        secret: String,
     ): Player
 
+    suspend fun update(player: Player): Player
+
     suspend fun findAll(): List<Player>
 
     suspend fun findById(id: BattleshipPlayerId): Player?
@@ -31,18 +67,25 @@ class InMemoryPlayerRepository(
     override suspend fun create(
         addr: String,
         name: String,
+        secret: String,
     ): Player {
         val player =
             Player(
                 id = BattleshipPlayerId(idGenerator.next()),
                 addr = addr,
                 name = name,
+                secret = secret,
             )
 
         players[player.id] = player
         return player
     }
 
+    override suspend fun update(player: Player): Player {
+        players[player.id] = player
+        return player
+    }
+
     override suspend fun findAll(): List<Player> {
         return players.values.toList()
     }
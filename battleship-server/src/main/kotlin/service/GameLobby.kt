@@ -1,36 +1,100 @@
 package dev.spris.battleship.server.service
 
+import dev.spris.battleship.core.BattleshipPlayerId
+import dev.spris.battleship.server.repository.DEFAULT_RULESET
+import dev.spris.battleship.server.repository.DEFAULT_VARIANT
 import dev.spris.battleship.server.repository.Player
 import dev.spris.battleship.server.repository.PlayerRepository
+import io.github.oshai.kotlinlogging.KotlinLogging
+import java.security.MessageDigest
 import kotlinx.coroutines.sync.Mutex
 import kotlinx.coroutines.sync.withLock
 import org.springframework.stereotype.Service
 
 const val ROUNDS_COUNT = 3
 
+private val logger = KotlinLogging.logger {}
+
 @Service
 class GameLobby(
     private val playerRepository: PlayerRepository,
     private val gameRunner: GameRunner,
+    private val playerDriverFactory: PlayerDriverFactory,
 ) {
     private val joinMutex = Mutex()
 
+    /** Players whose games are scheduled, guarded by [joinMutex]. */
+    private val scheduled = mutableSetOf<BattleshipPlayerId>()
+
+    /**
+     * Adds a player and schedules games against every other player supporting the default ruleset
+     * and variant. A name joined with a secret is reserved: joining with the same name and secret
+     * again only updates the player address.
+     */
     suspend fun join(
         addr: String,
         name: String,
+        secret: String = "",
     ) {
-        var players: List<Player>
         var newPlayer: Player
+        var rejoined = false
 
         joinMutex.withLock {
-            players = playerRepository.findAll()
+            val existing = playerRepository.findAll().find { it.name == name }
 
-            require(!players.any { it.name == name }) { "Player name $name is already taken" }
+            if (existing != null) {
+                require(existing.secret.isNotEmpty() && secretsMatch(existing.secret, secret)) {
+                    "Player name $name is already taken"
+                }
 
-            newPlayer = playerRepository.create(addr, name)
+                newPlayer = playerRepository.update(existing.copy(addr = addr))
+                rejoined = true
+            } else {
+                newPlayer = playerRepository.create(addr, name, secret)
+            }
         }
 
-        for (player in players) {
+        // requested outside the lock, so a slow bot doesn't hold up other players joining
+        val info = playerDriverFactory.create(newPlayer).requestInfo()
+
+        val opponents = mutableListOf<Player>()
+
+        // players are updated under the lock, so the info doesn't overwrite the address of a
+        // concurrent rejoin, and opponents are only players scheduled already, with their info
+        // set, so games of players joining at once are scheduled by one of them
+        joinMutex.withLock {
+            val current = playerRepository.findById(newPlayer.id) ?: newPlayer
+            newPlayer = playerRepository.update(current.copy(info = info))
+
+            if (rejoined) {
+                return@withLock
+            }
+
+            for (player in playerRepository.findAll()) {
+                if (player.id == newPlayer.id || player.id !in scheduled) {
+                    continue
+                }
+
+                if (!canPlay(player, newPlayer, DEFAULT_RULESET, DEFAULT_VARIANT)) {
+                    logger.info {
+                        "Skipping games of $player and $newPlayer, " +
+                            "$DEFAULT_RULESET/$DEFAULT_VARIANT is not supported by both"
+                    }
+                    continue
+                }
+
+                opponents.add(player)
+            }
+
+            scheduled.add(newPlayer.id)
+        }
+
+        if (rejoined) {
+            logger.info { "Player $newPlayer rejoined the lobby" }
+            return
+        }
+
+        for (player in opponents) {
             for (i in 0 ..< ROUNDS_COUNT) {
                 val even = i % 2 == 0
                 val firstPlayer = if (even) player else newPlayer
@@ -41,3 +105,15 @@ class GameLobby(
         }
     }
 }
+
+private fun canPlay(
+    player1: Player,
+    player2: Player,
+    ruleset: String,
+    variant: String,
+) = player1.supports(ruleset, variant) && player2.supports(ruleset, variant)
+
+private fun secretsMatch(
+    a: String,
+    b: String,
+) = MessageDigest.isEqual(a.toByteArray(), b.toByteArray())
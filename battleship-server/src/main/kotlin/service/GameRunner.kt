@@ -4,6 +4,7 @@ import dev.spris.battleship.core.*
 import dev.spris.battleship.server.repository.GameRepository
 import dev.spris.battleship.server.repository.GameState
 import dev.spris.battleship.server.repository.Player
+import dev.spris.battleship.server.repository.PlayerRepository
 import io.github.oshai.kotlinlogging.KotlinLogging
 import jakarta.annotation.PostConstruct
 import jakarta.annotation.PreDestroy
@@ -20,6 +21,7 @@ private val logger = KotlinLogging.logger {}
 class GameRunner(
     private val playerDriverFactory: PlayerDriverFactory,
     private val gameRepository: GameRepository,
+    private val playerRepository: PlayerRepository,
 ) {
     private val scope = CoroutineScope(Dispatchers.IO + SupervisorJob())
     private val games = Channel<Pair<Player, Player>>(UNLIMITED)
@@ -91,16 +93,51 @@ class GameRunner(
         player1: Player,
         player2: Player,
     ) {
+        // players could have rejoined the lobby with a new address since the game was scheduled
         val players =
             mapOf(
-                player1.id to playerDriverFactory.create(player1),
-                player2.id to playerDriverFactory.create(player2),
+                player1.id to
+                    playerDriverFactory.create(playerRepository.findById(player1.id) ?: player1),
+                player2.id to
+                    playerDriverFactory.create(playerRepository.findById(player2.id) ?: player2),
             )
 
+        val names = mapOf(player1.id to player1.name, player2.id to player2.name)
+
+        try {
+            playGame(game, players, names)
+        } finally {
+            val winnerId = (game.state as? BattleshipStateGameOver)?.winnerId
+
+            withContext(NonCancellable) {
+                for ((playerId, driver) in players) {
+                    try {
+                        driver.gameOver(
+                            game.gameId,
+                            won = playerId == winnerId,
+                            opponentField = game.playerFieldOrNull(game.otherPlayerId(playerId)),
+                        )
+                    } catch (e: Exception) {
+                        logger.warn { "Failed to end game ${game.gameId} for $playerId: $e" }
+                    }
+                }
+            }
+        }
+    }
+
+    private suspend fun playGame(
+        game: BattleshipGame,
+        players: Map<BattleshipPlayerId, PlayerDriver>,
+        names: Map<BattleshipPlayerId, String>,
+    ) {
         for (turn in 0..GAME_TURNS_LIMIT) {
             when (val state = game.state) {
                 is BattleshipStateAwaitingField -> {
-                    val field = players[state.playerId]!!.requestField(game.gameId)
+                    val field =
+                        players[state.playerId]!!.requestField(
+                            gameId = game.gameId,
+                            opponentName = names[game.otherPlayerId(state.playerId)]!!,
+                        )
                     game.accept(BattleshipActionField(state.playerId, field))
                 }
                 is BattleshipStateAwaitingStrike -> {
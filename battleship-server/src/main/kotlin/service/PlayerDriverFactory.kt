@@ -3,24 +3,66 @@ package dev.spris.battleship.server.service
 import com.google.common.cache.CacheBuilder
 import com.google.common.cache.CacheLoader
 import dev.spris.battleship.core.*
+import dev.spris.battleship.proto.bot.v1.BattleshipBotServiceGrpc
 import dev.spris.battleship.proto.bot.v1.BattleshipBotServiceGrpcKt
 import dev.spris.battleship.proto.bot.v1.getFieldRequest
+import dev.spris.battleship.proto.bot.v1.getInfoRequest
+import dev.spris.battleship.proto.bot.v1.PlayGameRequest
+import dev.spris.battleship.proto.bot.v1.PlayGameResponse
+import dev.spris.battleship.proto.bot.v1.gameOverEvent
+import dev.spris.battleship.proto.bot.v1.gameStartEvent
 import dev.spris.battleship.proto.bot.v1.getStrikeRequest
+import dev.spris.battleship.proto.bot.v1.playGameRequest
+import dev.spris.battleship.proto.bot.v1.strikeOutcomeEvent
+import dev.spris.battleship.proto.bot.v1.turnEvent
+import dev.spris.battleship.server.config.GrpcConfig
 import dev.spris.battleship.server.grpc.toDomain
 import dev.spris.battleship.server.grpc.toOtherFieldProto
 import dev.spris.battleship.server.grpc.toProto
+import dev.spris.battleship.server.repository.BotInfo
+import dev.spris.battleship.server.repository.DEFAULT_RULESET
+import dev.spris.battleship.server.repository.DEFAULT_VARIANT
+import dev.spris.battleship.server.repository.PLAY_GAME_STREAM_CAPABILITY
+import dev.spris.battleship.server.repository.PROTOCOL_VERSION
 import dev.spris.battleship.server.repository.Player
-import io.grpc.ManagedChannelBuilder
+import io.github.oshai.kotlinlogging.KotlinLogging
+import io.grpc.ChannelCredentials
+import io.grpc.Grpc
+import io.grpc.InsecureChannelCredentials
+import io.grpc.Metadata
+import io.grpc.StatusException
+import io.grpc.TlsChannelCredentials
+import java.io.File
+import java.util.concurrent.ConcurrentHashMap
+import java.util.concurrent.TimeUnit
 import kotlin.random.Random
+import kotlinx.coroutines.CoroutineScope
+import kotlinx.coroutines.Dispatchers
+import kotlinx.coroutines.SupervisorJob
+import kotlinx.coroutines.cancel
+import kotlinx.coroutines.channels.Channel
+import kotlinx.coroutines.channels.Channel.Factory.UNLIMITED
 import kotlinx.coroutines.delay
+import kotlinx.coroutines.flow.consumeAsFlow
+import kotlinx.coroutines.flow.produceIn
+import kotlinx.coroutines.withTimeoutOrNull
 import org.springframework.stereotype.Service
 
+const val GET_INFO_TIMEOUT_SECONDS = 5L
+const val PLAY_GAME_CLOSE_TIMEOUT_MILLIS = 1_000L
+
+private val logger = KotlinLogging.logger {}
+
 @Service
-class PlayerDriverFactory {
+class PlayerDriverFactory(
+    config: GrpcConfig,
+) {
+    private val credentials = botChannelCredentials(config.bots.tls)
+
     private val grpcPlayers =
         CacheBuilder.newBuilder()
             .build<Player, GrpcPlayerDriver>(
-                CacheLoader.from { player -> GrpcPlayerDriver(player) }
+                CacheLoader.from { player -> GrpcPlayerDriver(player, credentials) }
             )
 
     suspend fun create(player: Player): PlayerDriver {
@@ -28,30 +70,108 @@ class PlayerDriverFactory {
             return InProcessRandomPlayerDriver(player)
         }
 
-        return grpcPlayers.get(player)
+        // info doesn't affect how the player is reached
+        val driver = grpcPlayers.get(player.copy(info = null))
+
+        if (player.hasCapability(PLAY_GAME_STREAM_CAPABILITY)) {
+            return StreamingPlayerDriver(driver)
+        }
+
+        return driver
+    }
+}
+
+fun botChannelCredentials(tls: GrpcConfig.GrpcBotsTls): ChannelCredentials {
+    if (!tls.enabled) {
+        return InsecureChannelCredentials.create()
+    }
+
+    val builder = TlsChannelCredentials.newBuilder()
+
+    if (tls.caFile.isNotBlank()) {
+        builder.trustManager(File(tls.caFile))
+    }
+
+    if (tls.certFile.isNotBlank()) {
+        builder.keyManager(File(tls.certFile), File(tls.keyFile))
     }
+
+    return builder.build()
 }
 
 interface PlayerDriver {
-    suspend fun requestField(gameId: BattleshipGameId): BattleshipField
+    /** Returns null if the bot doesn't implement GetInfo or fails to answer. */
+    suspend fun requestInfo(): BotInfo?
+
+    /** [opponentName] is passed on to the bot, so it can model its opponents across games. */
+    suspend fun requestField(
+        gameId: BattleshipGameId,
+        opponentName: String,
+    ): BattleshipField
 
     suspend fun requestStrike(
         gameId: BattleshipGameId,
         ownField: BattleshipField,
         otherField: BattleshipField,
     ): BattleshipPos
+
+    /**
+     * Called once the game is finished, or failed. [opponentField] is null if the opponent never
+     * provided its field.
+     */
+    suspend fun gameOver(
+        gameId: BattleshipGameId,
+        won: Boolean,
+        opponentField: BattleshipField?,
+    ) {}
 }
 
 class GrpcPlayerDriver(
-    player: Player,
+    val player: Player,
+    credentials: ChannelCredentials,
 ) : PlayerDriver {
-    private val channel = ManagedChannelBuilder.forTarget(player.addr).usePlaintext().build()
+    private val channel = Grpc.newChannelBuilder(player.addr, credentials).build()
 
     private val stub = BattleshipBotServiceGrpcKt.BattleshipBotServiceCoroutineStub(channel)
 
-    override suspend fun requestField(gameId: BattleshipGameId): BattleshipField {
-        val request = getFieldRequest { this.gameId = gameId.id }
-        val response = stub.getField(request)
+    override suspend fun requestInfo(): BotInfo? {
+        val request = getInfoRequest {}
+        val headers =
+            RequestSigner.headers(
+                player.secret,
+                BattleshipBotServiceGrpc.getGetInfoMethod().fullMethodName,
+                "",
+                request,
+            )
+
+        return try {
+            stub
+                .withDeadlineAfter(GET_INFO_TIMEOUT_SECONDS, TimeUnit.SECONDS)
+                .getInfo(request, headers)
+                .info
+                .toDomain()
+        } catch (e: StatusException) {
+            logger.warn { "Failed to get info of $player: ${e.status}" }
+            null
+        }
+    }
+
+    override suspend fun requestField(
+        gameId: BattleshipGameId,
+        opponentName: String,
+    ): BattleshipField {
+        val request = getFieldRequest {
+            this.gameId = gameId.id
+            this.opponentName = opponentName
+        }
+        val headers =
+            RequestSigner.headers(
+                player.secret,
+                BattleshipBotServiceGrpc.getGetFieldMethod().fullMethodName,
+                gameId.id,
+                request,
+            )
+        val response = stub.getField(request, headers)
 
         return BattleshipField(
             field = BattleshipField.fieldArrayFromString(response.field),
@@ -71,16 +191,192 @@ class GrpcPlayerDriver(
             this.otherField = otherField.toOtherFieldProto()
         }
 
-        val response = stub.getStrike(request)
+        val headers =
+            RequestSigner.headers(
+                player.secret,
+                BattleshipBotServiceGrpc.getGetStrikeMethod().fullMethodName,
+                gameId.id,
+                request,
+            )
+        val response = stub.getStrike(request, headers)
 
         return response.pos.toDomain()
     }
+
+    fun playGame(gameId: BattleshipGameId): PlayGameSession {
+        val headers =
+            RequestSigner.headers(
+                player.secret,
+                BattleshipBotServiceGrpc.getPlayGameMethod().fullMethodName,
+                gameId.id,
+                null,
+            )
+        headers.put(RequestSigner.GAME_ID_KEY, gameId.id)
+
+        return PlayGameSession(stub, headers)
+    }
+}
+
+/** A PlayGame stream of a single game. */
+class PlayGameSession(
+    stub: BattleshipBotServiceGrpcKt.BattleshipBotServiceCoroutineStub,
+    headers: Metadata,
+) {
+    private val scope = CoroutineScope(Dispatchers.IO + SupervisorJob())
+    private val requests = Channel<PlayGameRequest>(UNLIMITED)
+    private val responses = stub.playGame(requests.consumeAsFlow(), headers).produceIn(scope)
+
+    suspend fun send(request: PlayGameRequest) {
+        requests.send(request)
+    }
+
+    suspend fun request(request: PlayGameRequest): PlayGameResponse {
+        send(request)
+
+        return responses.receiveCatching().getOrNull()
+            ?: throw IllegalStateException("PlayGame stream was closed by the bot")
+    }
+
+    /** Ends the stream, waiting for the bot to close it for at most [timeoutMillis]. */
+    suspend fun close(timeoutMillis: Long = PLAY_GAME_CLOSE_TIMEOUT_MILLIS) {
+        requests.close()
+
+        try {
+            withTimeoutOrNull(timeoutMillis) {
+                for (response in responses) {
+                    logger.warn { "Unexpected PlayGame response after game over: $response" }
+                }
+            }
+        } catch (e: StatusException) {
+            logger.warn { "PlayGame stream failed after game over: ${e.status}" }
+        } finally {
+            scope.cancel()
+        }
+    }
+}
+
+/**
+ * Plays games over PlayGame streams, with a stream per game. Bots get strike outcomes instead of
+ * full fields, so outcomes not sent yet are tracked per game.
+ */
+class StreamingPlayerDriver(
+    private val driver: GrpcPlayerDriver,
+) : PlayerDriver {
+    private class Game(
+        val session: PlayGameSession,
+        val sentOwn: MutableSet<BattleshipPos> = mutableSetOf(),
+        val sentOther: MutableSet<BattleshipPos> = mutableSetOf(),
+    )
+
+    private val games = ConcurrentHashMap<BattleshipGameId, Game>()
+
+    override suspend fun requestInfo(): BotInfo? = driver.requestInfo()
+
+    override suspend fun requestField(
+        gameId: BattleshipGameId,
+        opponentName: String,
+    ): BattleshipField {
+        val game = Game(driver.playGame(gameId))
+        games.put(gameId, game)?.session?.close()
+
+        val response =
+            game.session.request(
+                playGameRequest {
+                    gameStart = gameStartEvent {
+                        this.gameId = gameId.id
+                        this.opponentName = opponentName
+                    }
+                }
+            )
+
+        check(response.hasField()) { "Expected field from ${driver.player}, got: $response" }
+
+        return BattleshipField(
+            field = BattleshipField.fieldArrayFromString(response.field),
+            hits = mutableSetOf(),
+            misses = mutableSetOf(),
+        )
+    }
+
+    override suspend fun requestStrike(
+        gameId: BattleshipGameId,
+        ownField: BattleshipField,
+        otherField: BattleshipField,
+    ): BattleshipPos {
+        val game = checkNotNull(games[gameId]) { "Game $gameId was not started" }
+
+        sendOutcomes(game.session, otherField, game.sentOther, own = true)
+        sendOutcomes(game.session, ownField, game.sentOwn, own = false)
+
+        val response = game.session.request(playGameRequest { turn = turnEvent {} })
+
+        check(response.hasStrike()) { "Expected strike from ${driver.player}, got: $response" }
+
+        return response.strike.toDomain()
+    }
+
+    override suspend fun gameOver(
+        gameId: BattleshipGameId,
+        won: Boolean,
+        opponentField: BattleshipField?,
+    ) {
+        val game = games.remove(gameId) ?: return
+
+        game.session.send(
+            playGameRequest {
+                gameOver = gameOverEvent {
+                    this.won = won
+                    opponentField?.let { this.opponentField = it.toProto() }
+                }
+            }
+        )
+        game.session.close()
+    }
+
+    private suspend fun sendOutcomes(
+        session: PlayGameSession,
+        field: BattleshipField,
+        sent: MutableSet<BattleshipPos>,
+        own: Boolean,
+    ) {
+        for ((positions, hit) in listOf(field.hits to true, field.misses to false)) {
+            for (pos in positions) {
+                if (!sent.add(pos)) {
+                    continue
+                }
+
+                session.send(
+                    playGameRequest {
+                        strikeOutcome = strikeOutcomeEvent {
+                            this.pos = pos.toProto()
+                            this.hit = hit
+                            this.own = own
+                        }
+                    }
+                )
+            }
+        }
+    }
 }
 
 class InProcessRandomPlayerDriver(
     private val player: Player,
 ) : PlayerDriver {
-    override suspend fun requestField(gameId: BattleshipGameId): BattleshipField {
+    override suspend fun requestInfo(): BotInfo {
+        return BotInfo(
+            name = player.name,
+            author = "battleship-server",
+            version = PROTOCOL_VERSION.toString(),
+            rulesets = listOf(DEFAULT_RULESET),
+            variants = listOf(DEFAULT_VARIANT),
+            protocolVersion = PROTOCOL_VERSION,
+        )
+    }
+
+    override suspend fun requestField(
+        gameId: BattleshipGameId,
+        opponentName: String,
+    ): BattleshipField {
         delay(500)
 
         return BattleshipField.fromShips(
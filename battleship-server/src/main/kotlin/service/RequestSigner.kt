@@ -0,0 +1,65 @@
+package dev.spris.battleship.server.service
+
+import com.google.protobuf.MessageLite
+import io.grpc.Metadata
+import java.security.MessageDigest
+import java.util.HexFormat
+import javax.crypto.Mac
+import javax.crypto.spec.SecretKeySpec
+
+const val MIN_SECRET_LENGTH = 16
+
+/**
+ * Signs requests to bots with the secret the bot joined the lobby with, so bots can reject requests
+ * that don't come from the server. Signature is hex encoded HMAC-SHA256 of the full method name,
+ * game id, unix milliseconds timestamp and hex encoded SHA-256 of the serialized request, separated
+ * by newlines. Streaming requests are signed before the first message is sent, with an empty body.
+ */
+object RequestSigner {
+    val SIGNATURE_KEY: Metadata.Key<String> =
+        Metadata.Key.of("x-battleship-signature", Metadata.ASCII_STRING_MARSHALLER)
+    val TIMESTAMP_KEY: Metadata.Key<String> =
+        Metadata.Key.of("x-battleship-timestamp", Metadata.ASCII_STRING_MARSHALLER)
+
+    /** Game id of streaming requests, which is signed before the first message is sent. */
+    val GAME_ID_KEY: Metadata.Key<String> =
+        Metadata.Key.of("x-battleship-game-id", Metadata.ASCII_STRING_MARSHALLER)
+
+    fun sign(
+        secret: String,
+        method: String,
+        gameId: String,
+        timestamp: Long,
+        body: ByteArray,
+    ): String {
+        val hex = HexFormat.of()
+        val bodyHash = hex.formatHex(MessageDigest.getInstance("SHA-256").digest(body))
+
+        val mac = Mac.getInstance("HmacSHA256")
+        mac.init(SecretKeySpec(secret.toByteArray(), "HmacSHA256"))
+
+        return hex.formatHex(mac.doFinal("$method\n$gameId\n$timestamp\n$bodyHash".toByteArray()))
+    }
+
+    /** Signing headers of the request, which is null for streaming requests. */
+    fun headers(
+        secret: String,
+        method: String,
+        gameId: String,
+        request: MessageLite?,
+    ): Metadata {
+        val headers = Metadata()
+
+        if (secret.isEmpty()) {
+            return headers
+        }
+
+        val timestamp = System.currentTimeMillis()
+        val body = request?.toByteArray() ?: ByteArray(0)
+
+        headers.put(TIMESTAMP_KEY, timestamp.toString())
+        headers.put(SIGNATURE_KEY, sign(secret, method, gameId, timestamp, body))
+
+        return headers
+    }
+}
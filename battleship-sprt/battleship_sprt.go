@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	_ "github.com/mtratsiuk/battleship/battleship-go-strategy"
+)
+
+// ConfigEnv names the environment variable pointing to the SPRT config file.
+const ConfigEnv = "BATTLESHIP_SPRT_CONFIG"
+
+type Config struct {
+	CandidatePlacement string  `yaml:"candidate_placement" toml:"candidate_placement" env:"BATTLESHIP_SPRT_CANDIDATE_PLACEMENT" flag:"candidate-placement" usage:"placement strategy name of the candidate"`
+	CandidateTargeting string  `yaml:"candidate_targeting" toml:"candidate_targeting" env:"BATTLESHIP_SPRT_CANDIDATE_TARGETING" flag:"candidate-targeting" usage:"targeting strategy name of the candidate"`
+	BaselinePlacement  string  `yaml:"baseline_placement" toml:"baseline_placement" env:"BATTLESHIP_SPRT_BASELINE_PLACEMENT" flag:"baseline-placement" usage:"placement strategy name of the baseline"`
+	BaselineTargeting  string  `yaml:"baseline_targeting" toml:"baseline_targeting" env:"BATTLESHIP_SPRT_BASELINE_TARGETING" flag:"baseline-targeting" usage:"targeting strategy name of the baseline"`
+	Elo0               float64 `yaml:"elo0" toml:"elo0" env:"BATTLESHIP_SPRT_ELO0" flag:"elo0" usage:"Elo difference of the null hypothesis, the candidate isn't better"`
+	Elo1               float64 `yaml:"elo1" toml:"elo1" env:"BATTLESHIP_SPRT_ELO1" flag:"elo1" usage:"Elo difference of the alternative hypothesis, the candidate is better"`
+	Alpha              float64 `yaml:"alpha" toml:"alpha" env:"BATTLESHIP_SPRT_ALPHA" flag:"alpha" usage:"probability of accepting a candidate that isn't better"`
+	Beta               float64 `yaml:"beta" toml:"beta" env:"BATTLESHIP_SPRT_BETA" flag:"beta" usage:"probability of rejecting a candidate that is better"`
+	MaxPairs           int     `yaml:"max_pairs" toml:"max_pairs" env:"BATTLESHIP_SPRT_MAX_PAIRS" flag:"max-pairs" usage:"number of game pairs after which the test stops inconclusive"`
+	Batch              int     `yaml:"batch" toml:"batch" env:"BATTLESHIP_SPRT_BATCH" flag:"batch" usage:"number of game pairs played between checks of the test"`
+	Seed               int     `yaml:"seed" toml:"seed" env:"BATTLESHIP_SPRT_SEED" flag:"seed" usage:"seed of the games"`
+	Workers            int     `yaml:"workers" toml:"workers" env:"BATTLESHIP_SPRT_WORKERS" flag:"workers" usage:"maximum number of games played at once"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.CandidatePlacement = "random"
+	c.CandidateTargeting = "random"
+	c.BaselinePlacement = "random"
+	c.BaselineTargeting = "random"
+	c.Elo0 = 0
+	c.Elo1 = 10
+	c.Alpha = 0.05
+	c.Beta = 0.05
+	c.MaxPairs = 50_000
+	c.Batch = 100
+	c.Seed = 1
+	c.Workers = runtime.NumCPU()
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if _, err := core.NewPlacementStrategy(c.CandidatePlacement); err != nil {
+		errs = append(errs, fmt.Errorf("candidate_placement: %w", err))
+	}
+
+	if _, err := core.NewTargetingStrategy(c.CandidateTargeting); err != nil {
+		errs = append(errs, fmt.Errorf("candidate_targeting: %w", err))
+	}
+
+	if _, err := core.NewPlacementStrategy(c.BaselinePlacement); err != nil {
+		errs = append(errs, fmt.Errorf("baseline_placement: %w", err))
+	}
+
+	if _, err := core.NewTargetingStrategy(c.BaselineTargeting); err != nil {
+		errs = append(errs, fmt.Errorf("baseline_targeting: %w", err))
+	}
+
+	if c.Elo1 <= c.Elo0 {
+		errs = append(errs, errors.New("elo1 must be greater than elo0"))
+	}
+
+	if c.Alpha <= 0 || c.Alpha >= 0.5 {
+		errs = append(errs, errors.New("alpha must be between 0 and 0.5"))
+	}
+
+	if c.Beta <= 0 || c.Beta >= 0.5 {
+		errs = append(errs, errors.New("beta must be between 0 and 0.5"))
+	}
+
+	if c.MaxPairs < 1 {
+		errs = append(errs, errors.New("max_pairs must be positive"))
+	}
+
+	if c.Batch < 1 {
+		errs = append(errs, errors.New("batch must be positive"))
+	}
+
+	if c.Workers < 1 {
+		errs = append(errs, errors.New("workers must be positive"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// main exits with 0 if the candidate is accepted, 1 if it's rejected or the
+// test is inconclusive, and 2 on errors.
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	candidate := Player{Name: "candidate", Placement: config.CandidatePlacement, Targeting: config.CandidateTargeting}
+	baseline := Player{Name: "baseline", Placement: config.BaselinePlacement, Targeting: config.BaselineTargeting}
+
+	fmt.Printf("SPRT of %v against %v: elo0 %v, elo1 %v, alpha %v, beta %v\n", candidate, baseline, config.Elo0, config.Elo1, config.Alpha, config.Beta)
+
+	test := NewSPRT(config.Elo0, config.Elo1, config.Alpha, config.Beta)
+	runner := NewRunner(candidate, baseline, config.Seed, config.Workers)
+
+	for test.Pairs() < config.MaxPairs && test.Decision() == DecisionContinue {
+		batch := min(config.Batch, config.MaxPairs-test.Pairs())
+
+		outcomes, err := runner.PlayPairs(ctx, test.Pairs(), batch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "test was interrupted: %v\n", err)
+			os.Exit(2)
+		}
+
+		for _, o := range outcomes {
+			test.Add(o)
+		}
+
+		fmt.Println(test.Progress())
+	}
+
+	fmt.Println(test.Summary())
+
+	if test.Decision() != DecisionAccept {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// Player is a side of the test, played in process with the named strategies.
+type Player struct {
+	Name      string
+	Placement string
+	Targeting string
+}
+
+func (p Player) String() string {
+	return fmt.Sprintf("%v (placement %v, targeting %v)", p.Name, p.Placement, p.Targeting)
+}
+
+func (p Player) Driver(seed int64) core.PlayerDriver {
+	placement, _ := core.NewPlacementStrategy(p.Placement)
+	targeting, _ := core.NewTargetingStrategy(p.Targeting)
+
+	return core.NewStrategyPlayerDriver(placement, targeting, rand.New(rand.NewSource(seed)))
+}
+
+// PairOutcome is the result of the candidate in a pair of games.
+type PairOutcome struct {
+	Wins   int
+	Draws  int
+	Losses int
+}
+
+// Points returns the candidate score of the pair in half points, 0 to 4.
+func (o PairOutcome) Points() int {
+	return o.Wins*2 + o.Draws
+}
+
+// Runner plays pairs of games between the candidate and the baseline. Both
+// games of a pair use the same two seeds, with sides swapping seeds and the
+// first move, so the luck of placement and targeting cancels out: identical
+// strategies always split a pair.
+type Runner struct {
+	candidate Player
+	baseline  Player
+	seed      int
+	workers   int
+}
+
+func NewRunner(candidate, baseline Player, seed, workers int) *Runner {
+	r := &Runner{}
+	r.candidate = candidate
+	r.baseline = baseline
+	r.seed = seed
+	r.workers = workers
+
+	return r
+}
+
+// PlayPairs plays count pairs starting from pair first, returning outcomes
+// in order, so results only depend on the seed and not on scheduling.
+func (r *Runner) PlayPairs(ctx context.Context, first, count int) ([]PairOutcome, error) {
+	outcomes := make([]PairOutcome, count)
+	sem := make(chan struct{}, r.workers)
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+
+	for i := 0; i < count && ctx.Err() == nil; i += 1 {
+		seedA, seedB := r.pairSeeds(first + i)
+
+		for game := 0; game < 2; game += 1 {
+			sem <- struct{}{}
+			wg.Add(1)
+
+			go func(i, game int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				var result int
+				if game == 0 {
+					result = r.playGame(ctx, r.candidate, seedA, r.baseline, seedB)
+				} else {
+					result = r.playGame(ctx, r.baseline, seedA, r.candidate, seedB)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch result {
+				case 1:
+					outcomes[i].Wins += 1
+				case 0:
+					outcomes[i].Draws += 1
+				default:
+					outcomes[i].Losses += 1
+				}
+			}(i, game)
+		}
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return outcomes, nil
+}
+
+func (r *Runner) pairSeeds(pair int) (int64, int64) {
+	base := int64(r.seed)<<32 + int64(pair)*2
+
+	return base, base + 1
+}
+
+// playGame plays a game where first strikes first, returning 1 if the
+// candidate wins, -1 if it loses and 0 for a draw. A game where a strategy
+// fails to move, e.g. having nowhere left to strike, is lost by that
+// strategy, and a game reaching core.BattleshipGameTurnsLimit is a draw.
+func (r *Runner) playGame(ctx context.Context, first Player, firstSeed int64, second Player, secondSeed int64) int {
+	g := core.NewBattleshipGame(core.NewId(), first.Name, second.Name)
+
+	players := map[string]core.PlayerDriver{
+		first.Name:  first.Driver(firstSeed),
+		second.Name: second.Driver(secondSeed),
+	}
+
+	err := core.PlayBattleshipGame(ctx, g, players)
+
+	winnerId, ok := g.WinnerId()
+	if !ok {
+		if err == nil || errors.Is(err, core.ErrGameTooLong) || ctx.Err() != nil {
+			return 0
+		}
+
+		// the game state isn't advanced by a failed move
+		winnerId = g.OtherPlayerId(g.State.PlayerId)
+	}
+
+	if winnerId == r.candidate.Name {
+		return 1
+	}
+
+	return -1
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Decision int
+
+const (
+	DecisionContinue Decision = iota
+	// DecisionAccept accepts the candidate: it's at least elo1 better.
+	DecisionAccept
+	// DecisionReject rejects the candidate: it's at most elo0 better.
+	DecisionReject
+)
+
+func (d Decision) String() string {
+	switch d {
+	case DecisionAccept:
+		return "candidate accepted"
+	case DecisionReject:
+		return "candidate rejected"
+	default:
+		return "inconclusive"
+	}
+}
+
+// SPRT is a generalized sequential probability ratio test of the Elo
+// difference between the candidate and the baseline, over pairs of games.
+// Pair scores follow a pentanomial distribution (0, 1/4, 1/2, 3/4 or 1 of
+// the candidate), the log-likelihood ratio is approximated from their mean
+// and variance, as done by chess engine testing frameworks.
+type SPRT struct {
+	elo0, elo1  float64
+	alpha, beta float64
+	// pentanomial counts pairs by candidate points in half points
+	pentanomial [5]int
+	wins        int
+	draws       int
+	losses      int
+}
+
+func NewSPRT(elo0, elo1, alpha, beta float64) *SPRT {
+	s := &SPRT{}
+	s.elo0 = elo0
+	s.elo1 = elo1
+	s.alpha = alpha
+	s.beta = beta
+
+	return s
+}
+
+func (s *SPRT) Add(o PairOutcome) {
+	s.pentanomial[o.Points()] += 1
+	s.wins += o.Wins
+	s.draws += o.Draws
+	s.losses += o.Losses
+}
+
+func (s *SPRT) Pairs() int {
+	pairs := 0
+	for _, n := range s.pentanomial {
+		pairs += n
+	}
+
+	return pairs
+}
+
+// Bounds returns the LLR at which the candidate is rejected and accepted.
+func (s *SPRT) Bounds() (float64, float64) {
+	return math.Log(s.beta / (1 - s.alpha)), math.Log((1 - s.beta) / s.alpha)
+}
+
+// meanVariance returns the mean and variance of pair scores.
+func (s *SPRT) meanVariance() (float64, float64) {
+	pairs := float64(s.Pairs())
+	if pairs == 0 {
+		return 0.5, 0
+	}
+
+	mean := 0.0
+	for points, n := range s.pentanomial {
+		mean += float64(points) / 4 * float64(n)
+	}
+	mean /= pairs
+
+	variance := 0.0
+	for points, n := range s.pentanomial {
+		d := float64(points)/4 - mean
+		variance += d * d * float64(n)
+	}
+	variance /= pairs
+
+	return mean, variance
+}
+
+// LLR returns the log-likelihood ratio of elo1 against elo0.
+func (s *SPRT) LLR() float64 {
+	pairs := s.Pairs()
+	if pairs == 0 {
+		return 0
+	}
+
+	mean, variance := s.meanVariance()
+
+	// pairs always scoring the same, e.g. identical strategies always
+	// splitting them, would make the ratio infinite
+	variance = max(variance, 1e-6)
+
+	s0, s1 := eloToScore(s.elo0), eloToScore(s.elo1)
+
+	return float64(pairs) * (s1 - s0) * (2*mean - s0 - s1) / (2 * variance)
+}
+
+func (s *SPRT) Decision() Decision {
+	lower, upper := s.Bounds()
+	llr := s.LLR()
+
+	switch {
+	case llr >= upper:
+		return DecisionAccept
+	case llr <= lower:
+		return DecisionReject
+	default:
+		return DecisionContinue
+	}
+}
+
+// Elo returns the estimated Elo difference and its 95% confidence margin.
+func (s *SPRT) Elo() (float64, float64) {
+	mean, variance := s.meanVariance()
+	margin := 1.96 * math.Sqrt(variance/float64(max(s.Pairs(), 1)))
+
+	elo := scoreToElo(mean)
+	low, high := scoreToElo(mean-margin), scoreToElo(mean+margin)
+
+	return elo, (high - low) / 2
+}
+
+func (s *SPRT) Progress() string {
+	lower, upper := s.Bounds()
+	elo, margin := s.Elo()
+
+	return fmt.Sprintf("pairs %v: LLR %.3f (%.3f, %.3f), elo %+.1f ± %.1f", s.Pairs(), s.LLR(), lower, upper, elo, margin)
+}
+
+func (s *SPRT) Summary() string {
+	elo, margin := s.Elo()
+
+	lines := []string{
+		fmt.Sprintf("Result: %v after %v pairs, %v games", s.Decision(), s.Pairs(), 2*s.Pairs()),
+		fmt.Sprintf("Games: %v wins, %v draws, %v losses", s.wins, s.draws, s.losses),
+		fmt.Sprintf("Pairs by candidate points 0, 0.5, 1, 1.5, 2: %v", s.pentanomial),
+		fmt.Sprintf("Elo: %+.1f ± %.1f (95%%)", elo, margin),
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// eloToScore returns the expected score of a player with the given Elo
+// difference against its opponent.
+func eloToScore(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// scoreToElo is the inverse of eloToScore, clamping scores of 0 and 1.
+func scoreToElo(score float64) float64 {
+	score = min(max(score, 1e-6), 1-1e-6)
+
+	return -400 * math.Log10(1/score-1)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// pairWithPoints returns a pair outcome worth points half points.
+func pairWithPoints(points int) PairOutcome {
+	switch points {
+	case 0:
+		return PairOutcome{Losses: 2}
+	case 1:
+		return PairOutcome{Draws: 1, Losses: 1}
+	case 2:
+		return PairOutcome{Wins: 1, Losses: 1}
+	case 3:
+		return PairOutcome{Wins: 1, Draws: 1}
+	default:
+		return PairOutcome{Wins: 2}
+	}
+}
+
+func TestDecision(t *testing.T) {
+	tests := []struct {
+		name        string
+		elo0, elo1  float64
+		pentanomial [5]int
+		expected    Decision
+	}{
+		{"identical strategies always splitting pairs", 0, 10, [5]int{0, 0, 100, 0, 0}, DecisionReject},
+		{"identical strategies after a single pair", 0, 10, [5]int{0, 0, 1, 0, 0}, DecisionReject},
+		{"candidate winning most pairs", 0, 10, [5]int{0, 0, 10, 40, 50}, DecisionAccept},
+		{"baseline winning most pairs", 0, 10, [5]int{50, 40, 10, 0, 0}, DecisionReject},
+		{"few even pairs", 0, 10, [5]int{1, 1, 1, 1, 1}, DecisionContinue},
+		{"no pairs", 0, 10, [5]int{}, DecisionContinue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewSPRT(test.elo0, test.elo1, 0.05, 0.05)
+
+			for points, n := range test.pentanomial {
+				for i := 0; i < n; i += 1 {
+					s.Add(pairWithPoints(points))
+				}
+			}
+
+			if s.pentanomial != test.pentanomial {
+				t.Fatalf("expected pentanomial %v, got %v", test.pentanomial, s.pentanomial)
+			}
+
+			if d := s.Decision(); d != test.expected {
+				t.Fatalf("expected %v, got %v with %v", test.expected, d, s.Progress())
+			}
+		})
+	}
+}
+
+func TestBounds(t *testing.T) {
+	tests := []struct {
+		alpha, beta float64
+	}{
+		{0.05, 0.05},
+		{0.01, 0.1},
+		{0.2, 0.02},
+	}
+
+	for _, test := range tests {
+		lower, upper := NewSPRT(0, 10, test.alpha, test.beta).Bounds()
+
+		expectedLower := math.Log(test.beta / (1 - test.alpha))
+		expectedUpper := math.Log((1 - test.beta) / test.alpha)
+
+		if math.Abs(lower-expectedLower) > 1e-12 || math.Abs(upper-expectedUpper) > 1e-12 {
+			t.Errorf("alpha %v, beta %v: expected bounds (%v, %v), got (%v, %v)", test.alpha, test.beta, expectedLower, expectedUpper, lower, upper)
+		}
+	}
+}
+
+func TestEloScoreRoundTrip(t *testing.T) {
+	for _, elo := range []float64{-800, -100, -10, 0, 5, 10, 100, 800} {
+		if got := scoreToElo(eloToScore(elo)); math.Abs(got-elo) > 1e-6 {
+			t.Errorf("expected elo %v, got %v", elo, got)
+		}
+	}
+
+	if score := eloToScore(0); score != 0.5 {
+		t.Errorf("expected even players to score 0.5, got %v", score)
+	}
+}
+
+func TestIdenticalStrategiesSplitPairs(t *testing.T) {
+	for _, targeting := range []string{"random", "hunt", "density"} {
+		t.Run(targeting, func(t *testing.T) {
+			candidate := Player{Name: "candidate", Placement: "random", Targeting: targeting}
+			baseline := Player{Name: "baseline", Placement: "random", Targeting: targeting}
+
+			outcomes, err := NewRunner(candidate, baseline, 1, 4).PlayPairs(context.Background(), 0, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, o := range outcomes {
+				if o.Wins != 1 || o.Losses != 1 {
+					t.Fatalf("pair %v: expected 1 win and 1 loss, got %+v", i, o)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"syscall"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	_ "github.com/mtratsiuk/battleship/battleship-go-strategy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ConfigEnv names the environment variable pointing to the tournament config file.
+const ConfigEnv = "BATTLESHIP_TOURNAMENT_CONFIG"
+
+const (
+	FormatRoundRobin        = "round_robin"
+	FormatSwiss             = "swiss"
+	FormatSingleElimination = "single_elimination"
+	FormatDoubleElimination = "double_elimination"
+)
+
+var Formats = []string{FormatRoundRobin, FormatSwiss, FormatSingleElimination, FormatDoubleElimination}
+
+// BotEntry is a bot taking part in the tournament, either called at its
+// address or played in process with the named strategies.
+type BotEntry struct {
+	Name      string `yaml:"name,omitempty" toml:"name,omitempty"`
+	Addr      string `yaml:"addr,omitempty" toml:"addr,omitempty"`
+	Secret    string `yaml:"secret,omitempty" toml:"secret,omitempty" secret:"true"`
+	Placement string `yaml:"placement,omitempty" toml:"placement,omitempty"`
+	Targeting string `yaml:"targeting,omitempty" toml:"targeting,omitempty"`
+}
+
+func (e BotEntry) IsInProcess() bool {
+	return e.Addr == ""
+}
+
+type Config struct {
+	Bots        []BotEntry    `yaml:"bots" toml:"bots"`
+	Addrs       []string      `yaml:"addrs" toml:"addrs" env:"BATTLESHIP_TOURNAMENT_ADDRS" flag:"addrs" usage:"addresses of bots to add to the bots of the config file"`
+	Format      string        `yaml:"format" toml:"format" env:"BATTLESHIP_TOURNAMENT_FORMAT" flag:"format" usage:"tournament format: round_robin, swiss, single_elimination or double_elimination"`
+	Games       int           `yaml:"games" toml:"games" env:"BATTLESHIP_TOURNAMENT_GAMES" flag:"games" usage:"number of games of every match, bots take turns to shoot first"`
+	Rounds      int           `yaml:"rounds" toml:"rounds" env:"BATTLESHIP_TOURNAMENT_ROUNDS" flag:"rounds" usage:"number of swiss rounds, log2 of the number of bots if 0"`
+	Workers     int           `yaml:"workers" toml:"workers" env:"BATTLESHIP_TOURNAMENT_WORKERS" flag:"workers" usage:"maximum number of games played at once"`
+	MoveTimeout time.Duration `yaml:"move_timeout" toml:"move_timeout" env:"BATTLESHIP_TOURNAMENT_MOVE_TIMEOUT" flag:"move-timeout" usage:"time a bot has to place its field or make a strike"`
+	Seed        int           `yaml:"seed" toml:"seed" env:"BATTLESHIP_TOURNAMENT_SEED" flag:"seed" usage:"seed of in-process bots, random if 0"`
+	Output      string        `yaml:"output" toml:"output" env:"BATTLESHIP_TOURNAMENT_OUTPUT" flag:"output" usage:"results format: text or json, json includes game logs"`
+	LogsDir     string        `yaml:"logs_dir" toml:"logs_dir" env:"BATTLESHIP_TOURNAMENT_LOGS_DIR" flag:"logs-dir" usage:"directory to write the GameProto of every game to as JSON"`
+	TLS         bool          `yaml:"tls" toml:"tls" env:"BATTLESHIP_TOURNAMENT_TLS" flag:"tls" usage:"connect to bots over TLS"`
+	TLSCAFile   string        `yaml:"tls_ca_file" toml:"tls_ca_file" env:"BATTLESHIP_TOURNAMENT_TLS_CA_FILE" flag:"tls-ca-file" usage:"CA certificates to verify bot certificates with, instead of system ones"`
+	TLSCertFile string        `yaml:"tls_cert_file" toml:"tls_cert_file" env:"BATTLESHIP_TOURNAMENT_TLS_CERT_FILE" flag:"tls-cert-file" usage:"client certificate presented to bots requiring mutual TLS"`
+	TLSKeyFile  string        `yaml:"tls_key_file" toml:"tls_key_file" env:"BATTLESHIP_TOURNAMENT_TLS_KEY_FILE" flag:"tls-key-file" usage:"client certificate key"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.Bots = make([]BotEntry, 0)
+	c.Addrs = make([]string, 0)
+	c.Format = FormatRoundRobin
+	c.Games = 10
+	c.Rounds = 0
+	c.Workers = runtime.NumCPU()
+	c.MoveTimeout = 10 * time.Second
+	c.Seed = 0
+	c.Output = "text"
+
+	return c
+}
+
+// Entries returns bots of the config file followed by bots of Addrs.
+func (c Config) Entries() []BotEntry {
+	entries := slices.Clone(c.Bots)
+
+	for _, addr := range c.Addrs {
+		entries = append(entries, BotEntry{Addr: addr})
+	}
+
+	return entries
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	entries := c.Entries()
+
+	if len(entries) < 2 {
+		errs = append(errs, errors.New("at least 2 bots are required, set bots or addrs"))
+	}
+
+	for i, e := range entries {
+		if !e.IsInProcess() {
+			if e.Placement != "" || e.Targeting != "" {
+				errs = append(errs, fmt.Errorf("bots[%v]: placement and targeting are only used by in-process bots, without addr", i))
+			}
+
+			continue
+		}
+
+		if e.Name == "" {
+			errs = append(errs, fmt.Errorf("bots[%v].name must not be empty", i))
+		}
+
+		if _, err := core.NewPlacementStrategy(valueOr(e.Placement, "random")); err != nil {
+			errs = append(errs, fmt.Errorf("bots[%v].placement: %w", i, err))
+		}
+
+		if _, err := core.NewTargetingStrategy(valueOr(e.Targeting, "random")); err != nil {
+			errs = append(errs, fmt.Errorf("bots[%v].targeting: %w", i, err))
+		}
+	}
+
+	if !slices.Contains(Formats, c.Format) {
+		errs = append(errs, fmt.Errorf("format: unknown format %q, expected one of %v", c.Format, Formats))
+	}
+
+	if c.Games < 1 {
+		errs = append(errs, errors.New("games must be positive"))
+	}
+
+	if c.Rounds < 0 {
+		errs = append(errs, errors.New("rounds must not be negative"))
+	}
+
+	if c.Workers < 1 {
+		errs = append(errs, errors.New("workers must be positive"))
+	}
+
+	if c.MoveTimeout <= 0 {
+		errs = append(errs, errors.New("move_timeout must be positive"))
+	}
+
+	if c.Output != "text" && c.Output != "json" {
+		errs = append(errs, fmt.Errorf("output: unknown format %q, expected text or json", c.Output))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("tls_cert_file and tls_key_file must be set together"))
+	}
+
+	if !c.TLS && (c.TLSCAFile != "" || c.TLSCertFile != "") {
+		errs = append(errs, errors.New("tls_ca_file, tls_cert_file and tls_key_file require tls"))
+	}
+
+	if _, err := c.DialOptions(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// DialOptions returns options of connections to bots.
+func (c Config) DialOptions() ([]grpc.DialOption, error) {
+	if !c.TLS {
+		return []grpc.DialOption{}, nil
+	}
+
+	config, err := core.NewClientTLSConfig(c.TLSCAFile, c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}, nil
+}
+
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	if config.Seed == 0 {
+		config.Seed = int(time.Now().UnixNano())
+	}
+
+	opts, err := config.DialOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to configure connections: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bots, err := NewBots(ctx, config.Entries(), config.MoveTimeout, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer func() {
+		for _, b := range bots {
+			b.Close()
+		}
+	}()
+
+	t := NewTournament(bots, config, NewRunner(config.Workers))
+
+	results, err := t.Run(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tournament was interrupted: %v\n", err)
+		os.Exit(1)
+	}
+
+	if config.LogsDir != "" {
+		if err := WriteGameLogs(config.LogsDir, results); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write game logs: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	if config.Output == "json" {
+		err = WriteJsonResults(os.Stdout, results)
+	} else {
+		err = WriteTextResults(os.Stdout, results)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write results: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+func valueOr(v, fallback string) string {
+	if v == "" {
+		return fallback
+	}
+
+	return v
+}
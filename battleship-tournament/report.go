@@ -0,0 +1,345 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Standing is the result of a bot in the tournament. Points are game wins in
+// round robin, match wins and byes in swiss, with a draw worth half, and the
+// number of rounds survived in elimination formats.
+type Standing struct {
+	Bot         *Bot
+	Seed        int
+	Points      float64
+	Tiebreak    float64
+	MatchWins   int
+	MatchDraws  int
+	MatchLosses int
+	Byes        int
+	GameWins    int
+	GameDraws   int
+	GameLosses  int
+	// Faults is the number of games forfeited by failing a move.
+	Faults int
+}
+
+// NewStandings ranks bots by points, then by the tiebreak (sum of opponents'
+// points in swiss, game wins otherwise), then by game wins and seed.
+func NewStandings(r Results) []Standing {
+	standings := make(map[*Bot]*Standing, len(r.Bots))
+
+	for i, b := range r.Bots {
+		standings[b] = &Standing{Bot: b, Seed: i + 1}
+	}
+
+	for _, m := range r.Matches {
+		a := standings[m.A]
+
+		if m.IsBye() {
+			a.Byes += 1
+			continue
+		}
+
+		b := standings[m.B]
+
+		switch m.Winner {
+		case nil:
+			a.MatchDraws += 1
+			b.MatchDraws += 1
+		case m.A:
+			a.MatchWins += 1
+			b.MatchLosses += 1
+		default:
+			b.MatchWins += 1
+			a.MatchLosses += 1
+		}
+	}
+
+	for _, g := range r.Games {
+		first, second := standings[g.First], standings[g.Second]
+
+		switch g.Winner {
+		case "":
+			first.GameDraws += 1
+			second.GameDraws += 1
+		case g.First.Name:
+			first.GameWins += 1
+			second.GameLosses += 1
+		default:
+			second.GameWins += 1
+			first.GameLosses += 1
+		}
+
+		if g.Fault == g.First.Name {
+			first.Faults += 1
+		} else if g.Fault == g.Second.Name {
+			second.Faults += 1
+		}
+	}
+
+	for _, s := range standings {
+		switch r.Format {
+		case FormatRoundRobin:
+			s.Points = float64(s.GameWins) + float64(s.GameDraws)/2
+			s.Tiebreak = float64(s.GameWins)
+		case FormatSwiss:
+			s.Points = float64(s.MatchWins+s.Byes) + float64(s.MatchDraws)/2
+		default:
+			s.Points = float64(r.Rounds + 1)
+			if round, ok := r.Eliminated[s.Bot]; ok {
+				s.Points = float64(round)
+			}
+			s.Tiebreak = float64(s.GameWins)
+		}
+	}
+
+	if r.Format == FormatSwiss {
+		for _, m := range r.Matches {
+			if !m.IsBye() {
+				standings[m.A].Tiebreak += standings[m.B].Points
+				standings[m.B].Tiebreak += standings[m.A].Points
+			}
+		}
+	}
+
+	ranked := make([]Standing, 0, len(standings))
+	for _, b := range r.Bots {
+		ranked = append(ranked, *standings[b])
+	}
+
+	slices.SortStableFunc(ranked, func(a, b Standing) int {
+		switch {
+		case a.Points != b.Points:
+			return compareDesc(a.Points, b.Points)
+		case a.Tiebreak != b.Tiebreak:
+			return compareDesc(a.Tiebreak, b.Tiebreak)
+		case a.GameWins != b.GameWins:
+			return b.GameWins - a.GameWins
+		default:
+			return a.Seed - b.Seed
+		}
+	})
+
+	return ranked
+}
+
+func compareDesc(a, b float64) int {
+	if a > b {
+		return -1
+	}
+
+	return 1
+}
+
+// HeadToHead returns game wins of every bot against every other bot.
+func HeadToHead(r Results) map[string]map[string]int {
+	h2h := make(map[string]map[string]int, len(r.Bots))
+
+	for _, b := range r.Bots {
+		h2h[b.Name] = make(map[string]int)
+	}
+
+	for _, g := range r.Games {
+		if g.Winner != "" {
+			h2h[g.Winner][g.Game.OtherPlayerId(g.Winner)] += 1
+		}
+	}
+
+	return h2h
+}
+
+func WriteTextResults(w io.Writer, r Results) error {
+	standings := NewStandings(r)
+	h2h := HeadToHead(r)
+
+	lines := []string{
+		fmt.Sprintf("Standings: %v, %v bots, %v matches, %v games", r.Format, len(r.Bots), len(r.Matches), len(r.Games)),
+		fmt.Sprintf("%4v  %-24v %7v %9v %9v %9v %6v", "#", "Bot", "Points", "Tiebreak", "Matches", "Games", "Faults"),
+	}
+
+	for i, s := range standings {
+		matches := fmt.Sprintf("%v-%v-%v", s.MatchWins, s.MatchDraws, s.MatchLosses)
+		games := fmt.Sprintf("%v-%v-%v", s.GameWins, s.GameDraws, s.GameLosses)
+
+		lines = append(lines, fmt.Sprintf("%4v  %-24v %7.1f %9.1f %9v %9v %6v", i+1, s.Bot.Name, s.Points, s.Tiebreak, matches, games, s.Faults))
+	}
+
+	lines = append(lines, "", "Head to head: game wins of the row bot against the column bot")
+
+	header := fmt.Sprintf("%4v  %-24v", "", "")
+	for i := range standings {
+		header += fmt.Sprintf(" %5v", i+1)
+	}
+	lines = append(lines, header)
+
+	for i, row := range standings {
+		line := fmt.Sprintf("%4v  %-24v", i+1, row.Bot.Name)
+
+		for _, col := range standings {
+			if row.Bot == col.Bot {
+				line += fmt.Sprintf(" %5v", "-")
+			} else {
+				line += fmt.Sprintf(" %5v", h2h[row.Bot.Name][col.Bot.Name])
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", "Matches")
+
+	for _, m := range r.Matches {
+		if m.IsBye() {
+			lines = append(lines, fmt.Sprintf("%4v  %-18v %v has a bye", m.Round, m.Stage, m.A.Name))
+			continue
+		}
+
+		winner := "draw"
+		if m.Winner != nil {
+			winner = "winner " + m.Winner.Name
+		}
+
+		lines = append(lines, fmt.Sprintf("%4v  %-18v %v %v - %v %v, %v", m.Round, m.Stage, m.A.Name, m.WinsA, m.WinsB, m.B.Name, winner))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+
+	return err
+}
+
+type jsonStanding struct {
+	Rank        int     `json:"rank"`
+	Bot         string  `json:"bot"`
+	Seed        int     `json:"seed"`
+	Points      float64 `json:"points"`
+	Tiebreak    float64 `json:"tiebreak"`
+	MatchWins   int     `json:"match_wins"`
+	MatchDraws  int     `json:"match_draws"`
+	MatchLosses int     `json:"match_losses"`
+	Byes        int     `json:"byes"`
+	GameWins    int     `json:"game_wins"`
+	GameDraws   int     `json:"game_draws"`
+	GameLosses  int     `json:"game_losses"`
+	Faults      int     `json:"faults"`
+}
+
+type jsonMatch struct {
+	Round  int    `json:"round"`
+	Stage  string `json:"stage"`
+	A      string `json:"a"`
+	B      string `json:"b,omitempty"`
+	WinsA  int    `json:"wins_a"`
+	WinsB  int    `json:"wins_b"`
+	Winner string `json:"winner,omitempty"`
+	Games  []int  `json:"games"`
+}
+
+type jsonGame struct {
+	Id     string          `json:"id"`
+	First  string          `json:"first"`
+	Second string          `json:"second"`
+	Winner string          `json:"winner,omitempty"`
+	Fault  string          `json:"fault,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	Game   json.RawMessage `json:"game"`
+}
+
+type jsonResults struct {
+	Format     string                    `json:"format"`
+	Standings  []jsonStanding            `json:"standings"`
+	HeadToHead map[string]map[string]int `json:"head_to_head"`
+	Matches    []jsonMatch               `json:"matches"`
+	Games      []jsonGame                `json:"games"`
+}
+
+// WriteJsonResults writes standings, the head to head matrix, matches and
+// games with their GameProto logs.
+func WriteJsonResults(w io.Writer, r Results) error {
+	out := jsonResults{}
+	out.Format = r.Format
+	out.Standings = make([]jsonStanding, 0, len(r.Bots))
+	out.HeadToHead = HeadToHead(r)
+	out.Matches = make([]jsonMatch, 0, len(r.Matches))
+	out.Games = make([]jsonGame, 0, len(r.Games))
+
+	for i, s := range NewStandings(r) {
+		out.Standings = append(out.Standings, jsonStanding{
+			Rank:        i + 1,
+			Bot:         s.Bot.Name,
+			Seed:        s.Seed,
+			Points:      s.Points,
+			Tiebreak:    s.Tiebreak,
+			MatchWins:   s.MatchWins,
+			MatchDraws:  s.MatchDraws,
+			MatchLosses: s.MatchLosses,
+			Byes:        s.Byes,
+			GameWins:    s.GameWins,
+			GameDraws:   s.GameDraws,
+			GameLosses:  s.GameLosses,
+			Faults:      s.Faults,
+		})
+	}
+
+	for _, m := range r.Matches {
+		jm := jsonMatch{Round: m.Round, Stage: m.Stage, A: m.A.Name, WinsA: m.WinsA, WinsB: m.WinsB, Games: m.Games}
+
+		if m.B != nil {
+			jm.B = m.B.Name
+		}
+
+		if m.Winner != nil {
+			jm.Winner = m.Winner.Name
+		}
+
+		out.Matches = append(out.Matches, jm)
+	}
+
+	for _, g := range r.Games {
+		b, err := protojson.Marshal(g.ToProto())
+		if err != nil {
+			return err
+		}
+
+		jg := jsonGame{Id: g.Game.Id, First: g.First.Name, Second: g.Second.Name, Winner: g.Winner, Fault: g.Fault, Game: b}
+
+		if g.Err != nil {
+			jg.Error = g.Err.Error()
+		}
+
+		out.Games = append(out.Games, jg)
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+
+	return e.Encode(out)
+}
+
+// WriteGameLogs writes the GameProto of every game as JSON to dir, files are
+// named by the number of the game in the results.
+func WriteGameLogs(dir string, r Results) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for i, g := range r.Games {
+		b, err := protojson.MarshalOptions{Multiline: true}.Marshal(g.ToProto())
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("game-%04d.json", i)), b, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
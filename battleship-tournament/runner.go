@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	pbbot "github.com/mtratsiuk/battleship/gen/proto/go/bot/v1"
+	pbcore "github.com/mtratsiuk/battleship/gen/proto/go/core/v1"
+	pbserver "github.com/mtratsiuk/battleship/gen/proto/go/server/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GetInfoTimeout is the timeout of GetInfo requests, made before the
+// tournament starts, so they aren't limited by the move timeout.
+const GetInfoTimeout = 5 * time.Second
+
+// Bot is a tournament participant, bots are identified by unique names.
+type Bot struct {
+	Name  string
+	Addr  string
+	Info  *pbcore.BotInfoProto
+	entry BotEntry
+	// driver is shared by all games of a remote bot
+	driver *core.BotPlayerDriver
+	close  func() error
+}
+
+// NewBots connects to remote bots, naming bots without a name after their
+// GetInfo name, or address.
+func NewBots(ctx context.Context, entries []BotEntry, moveTimeout time.Duration, opts []grpc.DialOption) ([]*Bot, error) {
+	bots := make([]*Bot, 0, len(entries))
+	names := make(map[string]bool)
+
+	closeAll := func() {
+		for _, b := range bots {
+			b.Close()
+		}
+	}
+
+	for _, e := range entries {
+		b, err := NewBot(ctx, e, moveTimeout, opts)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		bots = append(bots, b)
+
+		if names[b.Name] {
+			closeAll()
+			return nil, fmt.Errorf("bot name %q is used more than once, set unique names in bots", b.Name)
+		}
+
+		names[b.Name] = true
+	}
+
+	return bots, nil
+}
+
+func NewBot(ctx context.Context, e BotEntry, moveTimeout time.Duration, opts []grpc.DialOption) (*Bot, error) {
+	b := &Bot{}
+	b.Name = e.Name
+	b.Addr = e.Addr
+	b.entry = e
+	b.close = func() error { return nil }
+
+	if e.IsInProcess() {
+		return b, nil
+	}
+
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+
+	conn, err := grpc.Dial(e.Addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v: %w", e.Addr, err)
+	}
+
+	client := pbbot.NewBattleshipBotServiceClient(conn)
+
+	info, err := core.NewBotPlayerDriver(client, e.Secret, GetInfoTimeout).Info(ctx)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to get info of %v: %w", e.Addr, err)
+	}
+
+	b.Info = info
+	b.driver = core.NewBotPlayerDriver(client, e.Secret, moveTimeout)
+	b.close = conn.Close
+
+	if b.Name == "" && info != nil {
+		b.Name = info.Name
+	}
+
+	if b.Name == "" {
+		b.Name = e.Addr
+	}
+
+	return b, nil
+}
+
+// Driver returns the driver of a single game. In-process bots get a new
+// driver every game, seeded with seed and the bot name.
+func (b *Bot) Driver(seed int64) core.PlayerDriver {
+	if b.driver != nil {
+		return b.driver
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(b.Name))
+
+	placement, _ := core.NewPlacementStrategy(valueOr(b.entry.Placement, "random"))
+	targeting, _ := core.NewTargetingStrategy(valueOr(b.entry.Targeting, "random"))
+
+	return core.NewStrategyPlayerDriver(placement, targeting, rand.New(rand.NewSource(seed^int64(h.Sum64()))))
+}
+
+func (b *Bot) ToProto() *pbserver.PlayerProto {
+	return &pbserver.PlayerProto{Id: b.Name, Name: b.Name, Info: b.Info}
+}
+
+func (b *Bot) Close() error {
+	return b.close()
+}
+
+// GameSpec is a game to play, the first bot places its field and strikes first.
+type GameSpec struct {
+	First  *Bot
+	Second *Bot
+	Seed   int64
+}
+
+// GameRecord is a played game. A bot failing a move forfeits the game, a
+// game aborted after core.BattleshipGameTurnsLimit turns is a draw.
+type GameRecord struct {
+	Game   *core.BattleshipGame
+	First  *Bot
+	Second *Bot
+	// Winner is empty for a draw.
+	Winner string
+	// Fault is the bot that failed a move, if any.
+	Fault string
+	Err   error
+}
+
+func (r GameRecord) ToProto() *pbserver.GameProto {
+	game := &pbserver.GameProto{}
+	game.Id = r.Game.Id
+	game.Player_1 = r.First.ToProto()
+	game.Player_2 = r.Second.ToProto()
+	game.State = pbserver.GameStateProto_FINISHED
+	game.Log = r.Game.LogToProto()
+
+	return game
+}
+
+// Runner plays games concurrently, at most workers at once.
+type Runner struct {
+	workers int
+	// play plays a single game, playGame unless faked in tests
+	play func(ctx context.Context, spec GameSpec) GameRecord
+}
+
+func NewRunner(workers int) *Runner {
+	return &Runner{workers: workers, play: playGame}
+}
+
+// Play plays the games, returning records in the order of specs.
+func (r *Runner) Play(ctx context.Context, specs []GameSpec) ([]GameRecord, error) {
+	records := make([]GameRecord, len(specs))
+	sem := make(chan struct{}, r.workers)
+	wg := sync.WaitGroup{}
+
+	for i, spec := range specs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, spec GameSpec) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			records[i] = r.play(ctx, spec)
+		}(i, spec)
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func playGame(ctx context.Context, spec GameSpec) GameRecord {
+	r := GameRecord{}
+	r.Game = core.NewBattleshipGame(core.NewId(), spec.First.Name, spec.Second.Name)
+	r.First = spec.First
+	r.Second = spec.Second
+
+	players := map[string]core.PlayerDriver{
+		spec.First.Name:  core.NewOpponentNamedDriver(spec.First.Driver(spec.Seed), spec.Second.Name),
+		spec.Second.Name: core.NewOpponentNamedDriver(spec.Second.Driver(spec.Seed), spec.First.Name),
+	}
+
+	r.Err = core.PlayBattleshipGame(ctx, r.Game, players)
+
+	if winnerId, ok := r.Game.WinnerId(); ok {
+		r.Winner = winnerId
+		return r
+	}
+
+	if r.Err != nil && !errors.Is(r.Err, core.ErrGameTooLong) {
+		// the game state isn't advanced by a failed move
+		r.Fault = r.Game.State.PlayerId
+		r.Winner = r.Game.OtherPlayerId(r.Fault)
+	}
+
+	return r
+}
@@ -0,0 +1,484 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// MaxDeciderGames is the number of extra games played to break a tie of an
+// elimination match, after which the higher seeded bot advances.
+const MaxDeciderGames = 9
+
+// MatchResult is a series of games between two bots, or a bye if B is nil.
+type MatchResult struct {
+	Round int
+	Stage string
+	A     *Bot
+	B     *Bot
+	WinsA int
+	WinsB int
+	// Winner is nil for a draw.
+	Winner *Bot
+	Games  []int
+}
+
+func (m MatchResult) IsBye() bool {
+	return m.B == nil
+}
+
+func (m MatchResult) Loser() *Bot {
+	switch m.Winner {
+	case nil:
+		return nil
+	case m.A:
+		return m.B
+	default:
+		return m.A
+	}
+}
+
+type Results struct {
+	Format  string
+	Bots    []*Bot
+	Matches []MatchResult
+	Games   []GameRecord
+	// Eliminated is the round each bot of an elimination format was
+	// knocked out in.
+	Eliminated map[*Bot]int
+	// Rounds is the number of played rounds.
+	Rounds int
+}
+
+type Tournament struct {
+	bots    []*Bot
+	config  Config
+	runner  *Runner
+	rng     *rand.Rand
+	results Results
+}
+
+// NewTournament creates a tournament of bots, seeded in the order of bots.
+func NewTournament(bots []*Bot, config Config, runner *Runner) *Tournament {
+	t := &Tournament{}
+	t.bots = bots
+	t.config = config
+	t.runner = runner
+	t.rng = rand.New(rand.NewSource(int64(config.Seed)))
+	t.results = Results{}
+	t.results.Format = config.Format
+	t.results.Bots = bots
+	t.results.Matches = make([]MatchResult, 0)
+	t.results.Games = make([]GameRecord, 0)
+	t.results.Eliminated = make(map[*Bot]int)
+
+	return t
+}
+
+func (t *Tournament) Run(ctx context.Context) (Results, error) {
+	var err error
+
+	switch t.config.Format {
+	case FormatRoundRobin:
+		err = t.runRoundRobin(ctx)
+	case FormatSwiss:
+		err = t.runSwiss(ctx)
+	case FormatSingleElimination:
+		err = t.runSingleElimination(ctx)
+	case FormatDoubleElimination:
+		err = t.runDoubleElimination(ctx)
+	default:
+		err = fmt.Errorf("unknown format %q", t.config.Format)
+	}
+
+	return t.results, err
+}
+
+type pairing struct {
+	a, b  *Bot
+	stage string
+}
+
+// playMatches plays all games of the round at once. Ties of decisive
+// matches are broken with decider games.
+func (t *Tournament) playMatches(ctx context.Context, pairings []pairing, decisive bool) ([]MatchResult, error) {
+	t.results.Rounds += 1
+
+	matches := make([]MatchResult, len(pairings))
+	specs := make([]GameSpec, 0)
+	owners := make([]int, 0)
+
+	for i, p := range pairings {
+		matches[i] = MatchResult{Round: t.results.Rounds, Stage: p.stage, A: p.a, B: p.b, Games: make([]int, 0)}
+
+		if p.b == nil {
+			matches[i].Winner = p.a
+			continue
+		}
+
+		for g := 0; g < t.config.Games; g += 1 {
+			specs = append(specs, t.gameSpec(p, g))
+			owners = append(owners, i)
+		}
+	}
+
+	if err := t.play(ctx, matches, specs, owners); err != nil {
+		return nil, err
+	}
+
+	for g := t.config.Games; decisive && g < t.config.Games+MaxDeciderGames; g += 1 {
+		specs = make([]GameSpec, 0)
+		owners = make([]int, 0)
+
+		for i, m := range matches {
+			if !m.IsBye() && m.WinsA == m.WinsB {
+				specs = append(specs, t.gameSpec(pairings[i], g))
+				owners = append(owners, i)
+			}
+		}
+
+		if len(specs) == 0 {
+			break
+		}
+
+		if err := t.play(ctx, matches, specs, owners); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range matches {
+		m := &matches[i]
+
+		switch {
+		case m.IsBye():
+		case m.WinsA > m.WinsB:
+			m.Winner = m.A
+		case m.WinsB > m.WinsA:
+			m.Winner = m.B
+		case decisive:
+			m.Winner = m.A
+		}
+	}
+
+	t.results.Matches = append(t.results.Matches, matches...)
+
+	return matches, nil
+}
+
+// gameSpec returns game g of the pairing, bots take turns to shoot first.
+func (t *Tournament) gameSpec(p pairing, g int) GameSpec {
+	if g%2 == 0 {
+		return GameSpec{First: p.a, Second: p.b, Seed: t.rng.Int63()}
+	}
+
+	return GameSpec{First: p.b, Second: p.a, Seed: t.rng.Int63()}
+}
+
+func (t *Tournament) play(ctx context.Context, matches []MatchResult, specs []GameSpec, owners []int) error {
+	records, err := t.runner.Play(ctx, specs)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range records {
+		m := &matches[owners[i]]
+		m.Games = append(m.Games, len(t.results.Games))
+		t.results.Games = append(t.results.Games, r)
+
+		switch r.Winner {
+		case m.A.Name:
+			m.WinsA += 1
+		case m.B.Name:
+			m.WinsB += 1
+		}
+	}
+
+	return nil
+}
+
+func (t *Tournament) runRoundRobin(ctx context.Context) error {
+	pairings := make([]pairing, 0)
+
+	for i := 0; i < len(t.bots); i += 1 {
+		for j := i + 1; j < len(t.bots); j += 1 {
+			pairings = append(pairings, pairing{t.bots[i], t.bots[j], "round robin"})
+		}
+	}
+
+	_, err := t.playMatches(ctx, pairings, false)
+
+	return err
+}
+
+// runSwiss pairs bots with equal or close points every round, never pairing
+// the same bots twice while possible. With an odd number of bots the lowest
+// ranked bot without a bye gets one, worth a win.
+func (t *Tournament) runSwiss(ctx context.Context) error {
+	rounds := t.config.Rounds
+	if rounds == 0 {
+		rounds = int(math.Ceil(math.Log2(float64(len(t.bots)))))
+	}
+
+	played := make(map[[2]*Bot]bool)
+	byes := make(map[*Bot]bool)
+
+	for r := 0; r < rounds; r += 1 {
+		stage := fmt.Sprintf("round %v", r+1)
+		standings := NewStandings(t.results)
+		ranked := make([]*Bot, 0, len(standings))
+		for _, s := range standings {
+			ranked = append(ranked, s.Bot)
+		}
+
+		pairings := make([]pairing, 0)
+
+		if len(ranked)%2 == 1 {
+			bye := len(ranked) - 1
+			for i := len(ranked) - 1; i >= 0; i -= 1 {
+				if !byes[ranked[i]] {
+					bye = i
+					break
+				}
+			}
+
+			byes[ranked[bye]] = true
+			pairings = append(pairings, pairing{ranked[bye], nil, stage})
+			ranked = slices.Delete(ranked, bye, bye+1)
+		}
+
+		pairs, ok := pairUnplayed(ranked, played)
+		if !ok {
+			pairs = pairAdjacent(ranked)
+		}
+
+		for _, p := range pairs {
+			played[[2]*Bot{p.a, p.b}] = true
+			played[[2]*Bot{p.b, p.a}] = true
+
+			pairings = append(pairings, pairing{p.a, p.b, stage})
+		}
+
+		if _, err := t.playMatches(ctx, pairings, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pairUnplayed pairs every ranked bot with the best ranked bot it hasn't
+// played yet, backtracking if the rest can't be paired. It returns false if
+// there are no such pairings.
+func pairUnplayed(ranked []*Bot, played map[[2]*Bot]bool) ([]pairing, bool) {
+	if len(ranked) == 0 {
+		return []pairing{}, true
+	}
+
+	a := ranked[0]
+
+	for i := 1; i < len(ranked); i += 1 {
+		if played[[2]*Bot{a, ranked[i]}] {
+			continue
+		}
+
+		rest := slices.Delete(slices.Clone(ranked), i, i+1)[1:]
+
+		if pairs, ok := pairUnplayed(rest, played); ok {
+			return append([]pairing{{a, ranked[i], ""}}, pairs...), true
+		}
+	}
+
+	return nil, false
+}
+
+// bracketOrder returns seeds in the order of a bracket of the given size,
+// so the best seeds meet as late as possible: 0, 3, 1, 2 for 4.
+func bracketOrder(size int) []int {
+	order := []int{0}
+
+	for len(order) < size {
+		next := make([]int, 0, len(order)*2)
+
+		for _, s := range order {
+			next = append(next, s, len(order)*2-1-s)
+		}
+
+		order = next
+	}
+
+	return order
+}
+
+// firstRound pairs seeded bots in bracket order, top seeds get byes if the
+// number of bots isn't a power of two.
+func (t *Tournament) firstRound() []pairing {
+	size := 1
+	for size < len(t.bots) {
+		size *= 2
+	}
+
+	order := bracketOrder(size)
+	pairings := make([]pairing, 0, size/2)
+
+	for i := 0; i < size; i += 2 {
+		a, b := order[i], order[i+1]
+		if a > b {
+			a, b = b, a
+		}
+
+		if b >= len(t.bots) {
+			pairings = append(pairings, pairing{t.bots[a], nil, ""})
+		} else {
+			pairings = append(pairings, pairing{t.bots[a], t.bots[b], ""})
+		}
+	}
+
+	return pairings
+}
+
+// pairAdjacent pairs bots in order, the last one gets a bye if their number is odd.
+func pairAdjacent(bots []*Bot) []pairing {
+	pairings := make([]pairing, 0, (len(bots)+1)/2)
+
+	for i := 0; i < len(bots); i += 2 {
+		if i+1 < len(bots) {
+			pairings = append(pairings, pairing{bots[i], bots[i+1], ""})
+		} else {
+			pairings = append(pairings, pairing{bots[i], nil, ""})
+		}
+	}
+
+	return pairings
+}
+
+func winners(matches []MatchResult) []*Bot {
+	bots := make([]*Bot, 0, len(matches))
+
+	for _, m := range matches {
+		bots = append(bots, m.Winner)
+	}
+
+	return bots
+}
+
+func (t *Tournament) runSingleElimination(ctx context.Context) error {
+	pairings := t.firstRound()
+
+	for {
+		stage := fmt.Sprintf("round of %v", len(pairings)*2)
+		if len(pairings) == 1 {
+			stage = "final"
+		}
+
+		for i := range pairings {
+			pairings[i].stage = stage
+		}
+
+		matches, err := t.playMatches(ctx, pairings, true)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range matches {
+			if loser := m.Loser(); loser != nil {
+				t.results.Eliminated[loser] = t.results.Rounds
+			}
+		}
+
+		if len(matches) == 1 {
+			return nil
+		}
+
+		pairings = pairAdjacent(winners(matches))
+	}
+}
+
+// runDoubleElimination knocks bots out after their second lost match. Every
+// round plays the winners bracket and the losers bracket at once, losers of
+// the winners bracket drop to the losers bracket. Winners of both brackets
+// meet in the grand final, which is replayed if the winners bracket
+// champion loses it.
+func (t *Tournament) runDoubleElimination(ctx context.Context) error {
+	upper := t.bots
+	lower := make([]*Bot, 0)
+
+	for round := 0; len(upper) > 1 || len(lower) > 1; round += 1 {
+		upperPairings := make([]pairing, 0)
+
+		switch {
+		case round == 0:
+			upperPairings = t.firstRound()
+		case len(upper) > 1:
+			upperPairings = pairAdjacent(upper)
+		}
+
+		lowerPairings := make([]pairing, 0)
+		if len(lower) > 1 {
+			lowerPairings = pairAdjacent(lower)
+		}
+
+		for i := range upperPairings {
+			upperPairings[i].stage = "winners bracket"
+		}
+
+		for i := range lowerPairings {
+			lowerPairings[i].stage = "losers bracket"
+		}
+
+		matches, err := t.playMatches(ctx, append(upperPairings, lowerPairings...), true)
+		if err != nil {
+			return err
+		}
+
+		upperMatches, lowerMatches := matches[:len(upperPairings)], matches[len(upperPairings):]
+
+		if len(upperMatches) > 0 {
+			upper = winners(upperMatches)
+		}
+
+		if len(lowerMatches) > 0 {
+			lower = winners(lowerMatches)
+		}
+
+		for _, m := range lowerMatches {
+			if loser := m.Loser(); loser != nil {
+				t.results.Eliminated[loser] = t.results.Rounds
+			}
+		}
+
+		for _, m := range upperMatches {
+			if loser := m.Loser(); loser != nil {
+				lower = append(lower, loser)
+			}
+		}
+	}
+
+	if len(lower) == 0 {
+		return nil
+	}
+
+	final := []pairing{{upper[0], lower[0], "grand final"}}
+
+	matches, err := t.playMatches(ctx, final, true)
+	if err != nil {
+		return err
+	}
+
+	if matches[0].Winner == upper[0] {
+		t.results.Eliminated[lower[0]] = t.results.Rounds
+		return nil
+	}
+
+	final[0].stage = "grand final reset"
+
+	matches, err = t.playMatches(ctx, final, true)
+	if err != nil {
+		return err
+	}
+
+	t.results.Eliminated[matches[0].Loser()] = t.results.Rounds
+
+	return nil
+}
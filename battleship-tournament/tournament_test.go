@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// newFakeTournament creates a tournament of n bots whose games are decided
+// by their seeds instead of being played: the first bot wins, the second
+// bot wins or it's a draw.
+func newFakeTournament(n int, config Config) *Tournament {
+	bots := make([]*Bot, 0, n)
+	for i := 0; i < n; i += 1 {
+		bots = append(bots, &Bot{Name: fmt.Sprintf("bot %v", i+1)})
+	}
+
+	runner := &Runner{workers: 1}
+	runner.play = func(ctx context.Context, spec GameSpec) GameRecord {
+		r := GameRecord{}
+		r.First = spec.First
+		r.Second = spec.Second
+
+		switch spec.Seed % 3 {
+		case 0:
+			r.Winner = spec.First.Name
+		case 1:
+			r.Winner = spec.Second.Name
+		}
+
+		return r
+	}
+
+	return NewTournament(bots, config, runner)
+}
+
+func TestBracketOrder(t *testing.T) {
+	tests := []struct {
+		size     int
+		expected []int
+	}{
+		{1, []int{0}},
+		{2, []int{0, 1}},
+		{4, []int{0, 3, 1, 2}},
+		{8, []int{0, 7, 3, 4, 1, 6, 2, 5}},
+	}
+
+	for _, test := range tests {
+		if order := bracketOrder(test.size); !slices.Equal(order, test.expected) {
+			t.Errorf("size %v: expected %v, got %v", test.size, test.expected, order)
+		}
+	}
+}
+
+// hasUnplayedPairings reports whether bots can all be paired without
+// pairing bots that already played.
+func hasUnplayedPairings(bots []*Bot, played map[[2]*Bot]bool) bool {
+	if len(bots) == 0 {
+		return true
+	}
+
+	for i := 1; i < len(bots); i += 1 {
+		if played[[2]*Bot{bots[0], bots[i]}] {
+			continue
+		}
+
+		rest := slices.Delete(slices.Clone(bots), i, i+1)[1:]
+		if hasUnplayedPairings(rest, played) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestSwiss(t *testing.T) {
+	for n := 2; n <= 9; n += 1 {
+		for seed := 1; seed <= 20; seed += 1 {
+			config := NewConfig()
+			config.Format = FormatSwiss
+			config.Games = 2
+			config.Rounds = n
+			config.Seed = seed
+
+			results, err := newFakeTournament(n, config).Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			played := make(map[[2]*Bot]bool)
+			byes := make(map[*Bot]int)
+
+			for r := 1; r <= results.Rounds; r += 1 {
+				round := make([]*Bot, 0)
+				repeated := false
+
+				for _, m := range results.Matches {
+					if m.Round != r {
+						continue
+					}
+
+					if m.IsBye() {
+						byes[m.A] += 1
+
+						// every bot gets a bye before anyone gets a second one
+						if byes[m.A] > 1 && len(byes) < n {
+							t.Fatalf("%v bots, seed %v, round %v: %v got a second bye before others got one", n, seed, r, m.A.Name)
+						}
+
+						continue
+					}
+
+					round = append(round, m.A, m.B)
+					repeated = repeated || played[[2]*Bot{m.A, m.B}]
+				}
+
+				if repeated && hasUnplayedPairings(round, played) {
+					t.Fatalf("%v bots, seed %v, round %v: bots were paired again though they didn't have to", n, seed, r)
+				}
+
+				for i := 0; i < len(round); i += 2 {
+					played[[2]*Bot{round[i], round[i+1]}] = true
+					played[[2]*Bot{round[i+1], round[i]}] = true
+				}
+			}
+
+			if n%2 == 1 && len(byes) != n {
+				t.Fatalf("%v bots, seed %v: expected every bot to get a bye in %v rounds, got %v", n, seed, n, byes)
+			}
+		}
+	}
+}
+
+func TestDoubleElimination(t *testing.T) {
+	for _, n := range []int{3, 5, 8} {
+		for seed := 1; seed <= 20; seed += 1 {
+			config := NewConfig()
+			config.Format = FormatDoubleElimination
+			config.Games = 1
+			config.Seed = seed
+
+			results, err := newFakeTournament(n, config).Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			losses := make(map[*Bot]int)
+			for _, m := range results.Matches {
+				if m.Winner == nil && !m.IsBye() {
+					t.Fatalf("%v bots, seed %v: match between %v and %v wasn't decided", n, seed, m.A.Name, m.B.Name)
+				}
+
+				if loser := m.Loser(); loser != nil {
+					losses[loser] += 1
+				}
+			}
+
+			standing := make([]*Bot, 0)
+
+			for _, b := range results.Bots {
+				if _, ok := results.Eliminated[b]; !ok {
+					standing = append(standing, b)
+					continue
+				}
+
+				if losses[b] != 2 {
+					t.Fatalf("%v bots, seed %v: %v was eliminated after %v losses", n, seed, b.Name, losses[b])
+				}
+			}
+
+			if len(standing) != 1 {
+				t.Fatalf("%v bots, seed %v: expected a single bot not eliminated, got %v", n, seed, len(standing))
+			}
+
+			if losses[standing[0]] > 1 {
+				t.Fatalf("%v bots, seed %v: winner %v lost %v matches", n, seed, standing[0].Name, losses[standing[0]])
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strconv"
+	"syscall"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+	_ "github.com/mtratsiuk/battleship/battleship-go-strategy"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigEnv names the environment variable pointing to the tuning config file.
+const ConfigEnv = "BATTLESHIP_TUNE_CONFIG"
+
+type Config struct {
+	Placement       string   `yaml:"placement" toml:"placement" env:"BATTLESHIP_TUNE_PLACEMENT" flag:"placement" usage:"placement strategy name to tune"`
+	Targeting       string   `yaml:"targeting" toml:"targeting" env:"BATTLESHIP_TUNE_TARGETING" flag:"targeting" usage:"targeting strategy name to tune"`
+	Params          []string `yaml:"params" toml:"params" env:"BATTLESHIP_TUNE_PARAMS" flag:"params" usage:"parameters to tune, e.g. targeting.hit_weight, all if empty"`
+	Iterations      int      `yaml:"iterations" toml:"iterations" env:"BATTLESHIP_TUNE_ITERATIONS" flag:"iterations" usage:"number of SPSA iterations"`
+	Pairs           int      `yaml:"pairs" toml:"pairs" env:"BATTLESHIP_TUNE_PAIRS" flag:"pairs" usage:"number of game pairs played every iteration"`
+	Rate            float64  `yaml:"rate" toml:"rate" env:"BATTLESHIP_TUNE_RATE" flag:"rate" usage:"learning rate, in steps of a parameter per unit of score difference"`
+	Seed            int      `yaml:"seed" toml:"seed" env:"BATTLESHIP_TUNE_SEED" flag:"seed" usage:"seed of perturbations and games"`
+	Workers         int      `yaml:"workers" toml:"workers" env:"BATTLESHIP_TUNE_WORKERS" flag:"workers" usage:"maximum number of games played at once"`
+	Checkpoint      string   `yaml:"checkpoint" toml:"checkpoint" env:"BATTLESHIP_TUNE_CHECKPOINT" flag:"checkpoint" usage:"file to save progress to and resume from, if it exists"`
+	CheckpointEvery int      `yaml:"checkpoint_every" toml:"checkpoint_every" env:"BATTLESHIP_TUNE_CHECKPOINT_EVERY" flag:"checkpoint-every" usage:"number of iterations between checkpoints"`
+	Output          string   `yaml:"output" toml:"output" env:"BATTLESHIP_TUNE_OUTPUT" flag:"output" usage:"bot config file to write tuned parameters to"`
+}
+
+func NewConfig() Config {
+	c := Config{}
+	c.Placement = "random"
+	c.Targeting = "density"
+	c.Params = make([]string, 0)
+	c.Iterations = 200
+	c.Pairs = 50
+	c.Rate = 1
+	c.Seed = 1
+	c.Workers = runtime.NumCPU()
+	c.Checkpoint = "tune.checkpoint.json"
+	c.CheckpointEvery = 10
+	c.Output = "tuned.yaml"
+
+	return c
+}
+
+func (c Config) Validate() error {
+	errs := make([]error, 0)
+
+	if _, err := core.NewPlacementStrategy(c.Placement); err != nil {
+		errs = append(errs, fmt.Errorf("placement: %w", err))
+	}
+
+	if _, err := core.NewTargetingStrategy(c.Targeting); err != nil {
+		errs = append(errs, fmt.Errorf("targeting: %w", err))
+	}
+
+	if len(errs) == 0 {
+		all := DefaultParams(c.Placement, c.Targeting)
+
+		if len(all) == 0 {
+			errs = append(errs, fmt.Errorf("strategies %v and %v have no parameters to tune", c.Placement, c.Targeting))
+		}
+
+		names := make([]string, 0, len(all))
+		for _, p := range all {
+			names = append(names, p.Key())
+		}
+
+		for _, name := range c.Params {
+			if !slices.Contains(names, name) {
+				errs = append(errs, fmt.Errorf("params: unknown parameter %q, expected one of %v", name, names))
+			}
+		}
+	}
+
+	if c.Iterations < 1 {
+		errs = append(errs, errors.New("iterations must be positive"))
+	}
+
+	if c.Pairs < 1 {
+		errs = append(errs, errors.New("pairs must be positive"))
+	}
+
+	if c.Rate <= 0 {
+		errs = append(errs, errors.New("rate must be positive"))
+	}
+
+	if c.Workers < 1 {
+		errs = append(errs, errors.New("workers must be positive"))
+	}
+
+	if c.CheckpointEvery < 1 {
+		errs = append(errs, errors.New("checkpoint_every must be positive"))
+	}
+
+	if c.Output == "" {
+		errs = append(errs, errors.New("output must not be empty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+func main() {
+	config := NewConfig()
+	core.MustLoadConfig(&config, ConfigEnv)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tuner := NewTuner(config)
+
+	if config.Checkpoint != "" {
+		resumed, err := tuner.Resume(config.Checkpoint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to resume from checkpoint: %v\n", err)
+			os.Exit(2)
+		}
+
+		if resumed {
+			fmt.Printf("Resumed from %v at iteration %v\n", config.Checkpoint, tuner.Iteration())
+		}
+	}
+
+	fmt.Printf("Tuning %v\n", tuner.ParamsString())
+
+	for tuner.Iteration() < config.Iterations {
+		score, err := tuner.Step(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "tuning was interrupted at iteration %v: %v\n", tuner.Iteration(), err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("iteration %v/%v: plus score %.3f, %v\n", tuner.Iteration(), config.Iterations, score, tuner.ParamsString())
+
+		if config.Checkpoint != "" && (tuner.Iteration()%config.CheckpointEvery == 0 || tuner.Iteration() == config.Iterations) {
+			if err := tuner.Save(config.Checkpoint); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to save checkpoint: %v\n", err)
+				os.Exit(2)
+			}
+		}
+	}
+
+	if err := WriteBotConfig(config.Output, tuner); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write bot config: %v\n", err)
+		os.Exit(2)
+	}
+
+	fmt.Printf("Wrote %v, run the bot with --config %v\n", config.Output, config.Output)
+}
+
+// botConfig is the part of the battleship-bot-go config set by tuning.
+type botConfig struct {
+	Placement       string             `yaml:"placement"`
+	PlacementParams map[string]float64 `yaml:"placement_params,omitempty"`
+	Targeting       string             `yaml:"targeting"`
+	TargetingParams map[string]float64 `yaml:"targeting_params,omitempty"`
+}
+
+// WriteBotConfig writes a battleship-bot-go config file with the strategies
+// and their tuned parameters.
+func WriteBotConfig(path string, t *Tuner) error {
+	c := botConfig{}
+	c.Placement = t.config.Placement
+	c.Targeting = t.config.Targeting
+	c.PlacementParams, c.TargetingParams = t.Params()
+
+	// tuning isn't that precise, round to keep the file readable
+	for _, params := range []map[string]float64{c.PlacementParams, c.TargetingParams} {
+		for name, value := range params {
+			params[name], _ = strconv.ParseFloat(strconv.FormatFloat(value, 'g', 4, 64), 64)
+		}
+	}
+
+	b := bytes.Buffer{}
+	fmt.Fprintf(&b, "# Tuned by battleship-tune in %v iterations of %v game pairs, seed %v.\n", t.Iteration(), t.config.Pairs, t.config.Seed)
+
+	e := yaml.NewEncoder(&b)
+	e.SetIndent(2)
+
+	if err := e.Encode(c); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b.Bytes(), 0o644)
+}
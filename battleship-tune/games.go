@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// Side is one of the two parameter sets playing each other in an iteration.
+type Side struct {
+	Name            string
+	Placement       string
+	PlacementParams map[string]float64
+	Targeting       string
+	TargetingParams map[string]float64
+}
+
+func (s Side) Driver(seed int64) core.PlayerDriver {
+	placement, _ := core.NewPlacementStrategyWithParams(s.Placement, s.PlacementParams)
+	targeting, _ := core.NewTargetingStrategyWithParams(s.Targeting, s.TargetingParams)
+
+	return core.NewStrategyPlayerDriver(placement, targeting, rand.New(rand.NewSource(seed)))
+}
+
+// PlayPairs plays pairs of games between the sides, returning the score of
+// a, from 0 to 1. Both games of a pair use the same two seeds, with sides
+// swapping seeds and the first move, so the luck of placement and targeting
+// cancels out.
+func PlayPairs(ctx context.Context, a, b Side, seed int64, pairs, workers int) (float64, error) {
+	points := 0
+	sem := make(chan struct{}, workers)
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+
+	for i := 0; i < pairs && ctx.Err() == nil; i += 1 {
+		seedA, seedB := seed+int64(i)*2, seed+int64(i)*2+1
+
+		for game := 0; game < 2; game += 1 {
+			sem <- struct{}{}
+			wg.Add(1)
+
+			go func(game int) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				var result int
+				if game == 0 {
+					result = playGame(ctx, a, seedA, b, seedB, a.Name)
+				} else {
+					result = playGame(ctx, b, seedA, a, seedB, a.Name)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				// points of a in half points
+				points += result + 1
+			}(game)
+		}
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return float64(points) / float64(pairs*4), nil
+}
+
+// playGame plays a game where first strikes first, returning 1 if side
+// wins, -1 if it loses and 0 for a draw. A game where a strategy fails to
+// move is lost by that strategy, and a game reaching
+// core.BattleshipGameTurnsLimit is a draw.
+func playGame(ctx context.Context, first Side, firstSeed int64, second Side, secondSeed int64, side string) int {
+	g := core.NewBattleshipGame(core.NewId(), first.Name, second.Name)
+
+	players := map[string]core.PlayerDriver{
+		first.Name:  first.Driver(firstSeed),
+		second.Name: second.Driver(secondSeed),
+	}
+
+	err := core.PlayBattleshipGame(ctx, g, players)
+
+	winnerId, ok := g.WinnerId()
+	if !ok {
+		if err == nil || errors.Is(err, core.ErrGameTooLong) || ctx.Err() != nil {
+			return 0
+		}
+
+		// the game state isn't advanced by a failed move
+		winnerId = g.OtherPlayerId(g.State.PlayerId)
+	}
+
+	if winnerId == side {
+		return 1
+	}
+
+	return -1
+}
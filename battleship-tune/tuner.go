@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+// Param is a parameter of the placement or targeting strategy, its Value
+// being the current estimate of the best value.
+type Param struct {
+	core.BattleshipStrategyParam
+	// Kind is "placement" or "targeting".
+	Kind  string
+	Tuned bool
+}
+
+// Key returns the name of the parameter prefixed with the kind of its
+// strategy, e.g. "targeting.hit_weight".
+func (p Param) Key() string {
+	return p.Kind + "." + p.Name
+}
+
+func (p Param) clamp(value float64) float64 {
+	return min(max(value, p.Min), p.Max)
+}
+
+// played returns the value strategies are played with, integer parameters
+// are rounded.
+func (p Param) played(value float64) float64 {
+	value = p.clamp(value)
+
+	if p.Integer {
+		return math.Round(value)
+	}
+
+	return value
+}
+
+// DefaultParams returns parameters of the strategies with their default values.
+func DefaultParams(placement, targeting string) []Param {
+	params := make([]Param, 0)
+
+	if s, err := core.NewPlacementStrategy(placement); err == nil {
+		for _, p := range core.StrategyParams(s) {
+			params = append(params, Param{BattleshipStrategyParam: p, Kind: "placement"})
+		}
+	}
+
+	if s, err := core.NewTargetingStrategy(targeting); err == nil {
+		for _, p := range core.StrategyParams(s) {
+			params = append(params, Param{BattleshipStrategyParam: p, Kind: "targeting"})
+		}
+	}
+
+	return params
+}
+
+// Tuner optimizes strategy parameters with simultaneous perturbation
+// stochastic approximation (SPSA): every iteration all tuned parameters are
+// randomly perturbed up or down at once, the plus side plays the minus side,
+// and parameters move towards the winning side.
+type Tuner struct {
+	config    Config
+	params    []Param
+	iteration int
+	play      func(ctx context.Context, a, b Side, seed int64, pairs, workers int) (float64, error)
+}
+
+func NewTuner(config Config) *Tuner {
+	t := &Tuner{}
+	t.config = config
+	t.params = DefaultParams(config.Placement, config.Targeting)
+	t.iteration = 0
+	t.play = PlayPairs
+
+	for i := range t.params {
+		t.params[i].Tuned = len(config.Params) == 0 || slices.Contains(config.Params, t.params[i].Key())
+	}
+
+	return t
+}
+
+func (t *Tuner) Iteration() int {
+	return t.iteration
+}
+
+// Step runs the next iteration, returning the score of the plus side.
+func (t *Tuner) Step(ctx context.Context) (float64, error) {
+	k := float64(t.iteration + 1)
+	stability := float64(t.config.Iterations) / 10
+
+	// gains decay as usual for SPSA, so perturbations and steps shrink
+	// as the estimate converges
+	ck := 1 / math.Pow(k, 0.101)
+	ak := t.config.Rate * math.Pow((1+stability)/(k+stability), 0.602)
+
+	// perturbations of an iteration don't depend on previous ones, so a
+	// resumed run perturbs the same way
+	rng := rand.New(rand.NewSource(int64(t.config.Seed)<<32 + int64(t.iteration)))
+
+	deltas := make([]float64, len(t.params))
+	for i, p := range t.params {
+		if p.Tuned {
+			deltas[i] = float64(rng.Intn(2)*2 - 1)
+		}
+	}
+
+	plus := t.side("plus", deltas, ck)
+	minus := t.side("minus", deltas, -ck)
+
+	score, err := t.play(ctx, plus, minus, int64(t.config.Seed)<<32+int64(t.iteration)<<16, t.config.Pairs, t.config.Workers)
+	if err != nil {
+		return 0, err
+	}
+
+	diff := 2*score - 1
+
+	for i := range t.params {
+		p := &t.params[i]
+		p.Value = p.clamp(p.Value + ak*ck*p.Step*diff*deltas[i])
+	}
+
+	t.iteration += 1
+
+	return score, nil
+}
+
+// side returns strategies with parameters perturbed by deltas scaled by
+// scale steps.
+func (t *Tuner) side(name string, deltas []float64, scale float64) Side {
+	perturbed := slices.Clone(t.params)
+	for i := range perturbed {
+		perturbed[i].Value += deltas[i] * scale * perturbed[i].Step
+	}
+
+	s := Side{}
+	s.Name = name
+	s.Placement = t.config.Placement
+	s.Targeting = t.config.Targeting
+	s.PlacementParams, s.TargetingParams = playedParams(perturbed)
+
+	return s
+}
+
+// Params returns the current values of placement and targeting parameters,
+// as they would be played.
+func (t *Tuner) Params() (map[string]float64, map[string]float64) {
+	return playedParams(t.params)
+}
+
+func playedParams(params []Param) (map[string]float64, map[string]float64) {
+	placement := make(map[string]float64)
+	targeting := make(map[string]float64)
+
+	for _, p := range params {
+		if p.Kind == "placement" {
+			placement[p.Name] = p.played(p.Value)
+		} else {
+			targeting[p.Name] = p.played(p.Value)
+		}
+	}
+
+	return placement, targeting
+}
+
+func (t *Tuner) ParamsString() string {
+	parts := make([]string, 0, len(t.params))
+
+	for _, p := range t.params {
+		if p.Tuned {
+			parts = append(parts, fmt.Sprintf("%v %.4g", p.Key(), p.played(p.Value)))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+type checkpoint struct {
+	Placement  string             `json:"placement"`
+	Targeting  string             `json:"targeting"`
+	Seed       int                `json:"seed"`
+	Iterations int                `json:"iterations"`
+	Iteration  int                `json:"iteration"`
+	Params     map[string]float64 `json:"params"`
+}
+
+// Save writes the progress to path, replacing the file only once it's
+// fully written.
+func (t *Tuner) Save(path string) error {
+	c := checkpoint{}
+	c.Placement = t.config.Placement
+	c.Targeting = t.config.Targeting
+	c.Seed = t.config.Seed
+	c.Iterations = t.config.Iterations
+	c.Iteration = t.iteration
+	c.Params = make(map[string]float64, len(t.params))
+
+	for _, p := range t.params {
+		c.Params[p.Key()] = p.Value
+	}
+
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Resume restores the progress saved to path, returning false if there is
+// no such file.
+func (t *Tuner) Resume(path string) (bool, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	c := checkpoint{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return false, fmt.Errorf("failed to parse %v: %w", path, err)
+	}
+
+	if c.Placement != t.config.Placement || c.Targeting != t.config.Targeting || c.Seed != t.config.Seed {
+		return false, fmt.Errorf("%v is a checkpoint of placement %v, targeting %v and seed %v, remove it to start over", path, c.Placement, c.Targeting, c.Seed)
+	}
+
+	// step sizes depend on the total number of iterations, so a run can't
+	// be resumed with another one
+	if c.Iterations != t.config.Iterations {
+		return false, fmt.Errorf("%v is a checkpoint of %v iterations, remove it to start over", path, c.Iterations)
+	}
+
+	for i := range t.params {
+		p := &t.params[i]
+
+		if value, ok := c.Params[p.Key()]; ok {
+			p.Value = p.clamp(value)
+		}
+	}
+
+	t.iteration = c.Iteration
+
+	return true, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+
+	core "github.com/mtratsiuk/battleship/battleship-go-core"
+)
+
+func TestParamPlayed(t *testing.T) {
+	float := Param{BattleshipStrategyParam: core.BattleshipStrategyParam{Name: "edge_bias", Min: 0.5, Max: 2, Step: 0.1}}
+	integer := Param{BattleshipStrategyParam: core.BattleshipStrategyParam{Name: "spacing", Min: 0, Max: 2, Step: 1, Integer: true}}
+
+	tests := []struct {
+		param    Param
+		value    float64
+		expected float64
+	}{
+		{float, 1.25, 1.25},
+		{float, 0.1, 0.5},
+		{float, 3, 2},
+		{integer, 1.49, 1},
+		{integer, 1.5, 2},
+		{integer, 0.6, 1},
+		{integer, -0.4, 0},
+		{integer, 3.7, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v %v", tt.param.Name, tt.value), func(t *testing.T) {
+			if actual := tt.param.played(tt.value); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func newTestConfig() Config {
+	config := NewConfig()
+	config.Placement = "spaced"
+	config.Targeting = "density"
+	config.Iterations = 20
+
+	return config
+}
+
+func TestTunerStep(t *testing.T) {
+	tests := []struct {
+		name  string
+		score float64
+		// direction of the change towards the plus side
+		direction float64
+	}{
+		{"plus side wins", 1, 1},
+		{"minus side wins", 0, -1},
+		{"draw", 0.5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tuner := NewTuner(newTestConfig())
+
+			var plus, minus Side
+			tuner.play = func(ctx context.Context, a, b Side, seed int64, pairs, workers int) (float64, error) {
+				plus, minus = a, b
+				return tt.score, nil
+			}
+
+			_, before := tuner.Params()
+
+			score, err := tuner.Step(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if score != tt.score {
+				t.Errorf("expected score %v, got %v", tt.score, score)
+			}
+
+			if tuner.Iteration() != 1 {
+				t.Errorf("expected iteration 1, got %v", tuner.Iteration())
+			}
+
+			placement, after := tuner.Params()
+
+			for name, value := range before {
+				towardsPlus := math.Copysign(1, plus.TargetingParams[name]-minus.TargetingParams[name])
+				change := after[name] - value
+
+				if tt.direction == 0 && change != 0 {
+					t.Errorf("expected %v not to change, got %v -> %v", name, value, after[name])
+				}
+
+				if tt.direction != 0 && (change == 0 || math.Copysign(1, change) != tt.direction*towardsPlus) {
+					t.Errorf("expected %v to move towards %v side, got %v -> %v", name, tt.name, value, after[name])
+				}
+			}
+
+			if spacing := placement["spacing"]; spacing != math.Round(spacing) || spacing < 0 || spacing > 2 {
+				t.Errorf("expected spacing to be a whole number within bounds, got %v", spacing)
+			}
+		})
+	}
+}
+
+func TestTunerResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	tuner := NewTuner(newTestConfig())
+	tuner.play = func(ctx context.Context, a, b Side, seed int64, pairs, workers int) (float64, error) {
+		return 0.75, nil
+	}
+
+	for i := 0; i < 3; i += 1 {
+		if _, err := tuner.Step(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tuner.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		resumed := NewTuner(newTestConfig())
+
+		ok, err := resumed.Resume(path)
+		if err != nil || !ok {
+			t.Fatalf("expected to resume, got %v, %v", ok, err)
+		}
+
+		if resumed.Iteration() != tuner.Iteration() {
+			t.Errorf("expected iteration %v, got %v", tuner.Iteration(), resumed.Iteration())
+		}
+
+		for i, p := range tuner.params {
+			if resumed.params[i].Value != p.Value {
+				t.Errorf("expected %v to be %v, got %v", p.Key(), p.Value, resumed.params[i].Value)
+			}
+		}
+	})
+
+	t.Run("missing checkpoint", func(t *testing.T) {
+		ok, err := NewTuner(newTestConfig()).Resume(filepath.Join(t.TempDir(), "missing.json"))
+		if err != nil || ok {
+			t.Errorf("expected nothing to resume, got %v, %v", ok, err)
+		}
+	})
+
+	mismatches := map[string]func(c *Config){
+		"targeting":  func(c *Config) { c.Targeting = "hunt" },
+		"seed":       func(c *Config) { c.Seed += 1 },
+		"iterations": func(c *Config) { c.Iterations += 1 },
+	}
+
+	for name, change := range mismatches {
+		t.Run("other "+name, func(t *testing.T) {
+			config := newTestConfig()
+			change(&config)
+
+			if _, err := NewTuner(config).Resume(path); err == nil {
+				t.Error("expected checkpoint to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+#!/usr/bin/env bash
+
+set -euo pipefail
+
+cd "$(dirname "$0")"/..
+
+# Generates a local CA, a certificate for the Go bot gRPC server and a client
+# certificate for the battleship server, for testing TLS and mutual TLS.
+
+CERTS_DIR=${1:-certs}
+BOT_HOSTS=${BATTLESHIP_BOT_GO_TLS_HOSTS:-DNS:localhost,DNS:battleship-bot-go,IP:127.0.0.1}
+
+mkdir -p "$CERTS_DIR"
+cd "$CERTS_DIR"
+
+echo "Generating CA..."
+openssl req -x509 -newkey rsa:2048 -nodes -days 365 \
+  -keyout ca.key -out ca.crt -subj "/CN=battleship-local-ca"
+
+echo "Generating bot certificate for $BOT_HOSTS..."
+openssl req -newkey rsa:2048 -nodes \
+  -keyout bot.key -out bot.csr -subj "/CN=battleship-bot-go"
+openssl x509 -req -days 365 -in bot.csr -CA ca.crt -CAkey ca.key -CAcreateserial \
+  -out bot.crt -extfile <(printf "subjectAltName=%s\nextendedKeyUsage=serverAuth" "$BOT_HOSTS")
+
+echo "Generating battleship server client certificate..."
+openssl req -newkey rsa:2048 -nodes \
+  -keyout server-client.key -out server-client.csr -subj "/CN=battleship-server"
+openssl x509 -req -days 365 -in server-client.csr -CA ca.crt -CAkey ca.key -CAcreateserial \
+  -out server-client.crt -extfile <(printf "extendedKeyUsage=clientAuth")
+
+rm -f ./*.csr
+
+echo "Certificates are written to $CERTS_DIR"